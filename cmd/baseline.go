@@ -0,0 +1,79 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// baselineCmd represents the baseline command which groups the baseline sub-commands
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Manage baselines of known findings",
+	Long:  "Manage baselines of known findings. Findings in a baseline given with --baseline are not reported by a scan.",
+}
+
+// baselineCreateCmd represents the baseline create command
+var baselineCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a baseline from the json output of a scan",
+	Long: "Create a baseline from the json output of a scan. The baseline only holds the fingerprint and location " +
+		"of each finding, never the secret, so it can be safely committed.\n\n" +
+		"Ex. wraith scanLocalGitRepo --local-repos . --json --silent | wraith baseline create --baseline-file .wraith-baseline.json",
+	Run: func(cmd *cobra.Command, args []string) {
+
+		scanType := "baseline"
+		sess := core.NewSession(scanType)
+
+		// Read the findings from a file or from stdin so the output of a scan can be piped in directly
+		var data []byte
+		var err error
+		findingsFile := viper.GetString("findings-file")
+		if findingsFile == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(core.SetHomeDir(findingsFile, sess))
+		}
+		if err != nil {
+			sess.Out.Error("Unable to read the findings: %s\n", err)
+			os.Exit(core.ExitCodeError)
+		}
+
+		findings, err := core.ReadFindings(data)
+		if err != nil {
+			sess.Out.Error("Unable to parse the findings, they must be the json output of a scan: %s\n", err)
+			os.Exit(core.ExitCodeError)
+		}
+
+		baselineFile := core.SetHomeDir(viper.GetString("baseline-file"), sess)
+		baseline := core.NewBaseline(findings, sess.WraithVersion)
+		if err := baseline.Save(baselineFile); err != nil {
+			sess.Out.Error("Unable to write the baseline %s: %s\n", baselineFile, err)
+			os.Exit(core.ExitCodeError)
+		}
+
+		sess.Out.Important("Wrote %d %s to %s\n", len(baseline.Findings), core.Pluralize(len(baseline.Findings), "finding", "findings"), baselineFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+	baselineCmd.AddCommand(baselineCreateCmd)
+
+	baselineCreateCmd.Flags().String("baseline-file", ".wraith-baseline.json", "File the baseline will be written to")
+	baselineCreateCmd.Flags().String("findings-file", "-", "File containing the json output of a scan, - reads from stdin")
+
+	err := viper.BindPFlag("baseline-file", baselineCreateCmd.Flags().Lookup("baseline-file"))
+	err = viper.BindPFlag("findings-file", baselineCreateCmd.Flags().Lookup("findings-file"))
+
+	if err != nil {
+		fmt.Printf("There was an error binding a flag: %s\n", err.Error())
+	}
+}
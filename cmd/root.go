@@ -19,6 +19,15 @@ var (
 		Use:   "wraith",
 		Short: "A tool to scan for secrets in various digital hiding spots",
 		Long:  "A tool to scan for secrets in various digital hiding spots - v" + version.AppVersion(), // TODO write a better long description
+
+		// Flags such as commit-depth are defined by several commands, and viper only keeps the last one bound
+		// to a key. Binding the flags of the command being run again makes sure its own flags are the ones used.
+		// Flags that are only defined by more than one command, such as scan-gists and repo-path, are only bound here.
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			if err := viper.BindPFlags(cmd.Flags()); err != nil {
+				fmt.Printf("There was an error binding a flag: %s\n", err.Error())
+			}
+		},
 	}
 )
 
@@ -27,47 +36,83 @@ var (
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(core.ExitCodeError)
 	}
 }
 
 func init() {
 	cobra.OnInitialize(core.SetConfig)
 
+	rootCmd.PersistentFlags().Bool("all-refs", false, "Scan the history of every branch and tag rather than only the default branch")
+	rootCmd.PersistentFlags().String("baseline", "", "Baseline file of known findings that will not be reported")
 	rootCmd.PersistentFlags().String("bind-address", "127.0.0.1", "The IP address for the webserver")
 	rootCmd.PersistentFlags().Int("bind-port", 9393, "The port for the webserver")
+	rootCmd.PersistentFlags().StringSlice("branches", nil, "Glob patterns of the branches to scan in addition to the default branch")
 	rootCmd.PersistentFlags().Int("confidence-level", 3, "The confidence level level of the expressions used to find matches")
 	rootCmd.PersistentFlags().String("config-file", "$HOME/.wraith/config.yaml", "config file")
+	rootCmd.PersistentFlags().Int("context-lines", 0, "Number of lines to show before and after each finding")
 	rootCmd.PersistentFlags().Bool("csv", false, "output csv format")
+	rootCmd.PersistentFlags().Bool("dedupe-across-repos", false, "Only match a blob once across every repository scanned, such as in forks or vendored files")
 	rootCmd.PersistentFlags().Bool("debug", false, "Print available debugging information to stdout")
+	rootCmd.PersistentFlags().StringSlice("exclude-signature-tags", nil, "Do not scan with signatures that have any of these tags")
+	rootCmd.PersistentFlags().StringSlice("exclude-signatures", nil, "Do not scan with these signature IDs, or signatures with IDs or descriptions that match these glob patterns")
+	rootCmd.PersistentFlags().Int("fail-on-confidence", 0, "Only exit with the findings exit code for findings from signatures with at least this confidence level")
+	rootCmd.PersistentFlags().StringSlice("fail-on-signature", nil, "Only exit with the findings exit code for findings from these signature IDs")
 	rootCmd.PersistentFlags().Bool("hide-secrets", false, "Do not print secrets to any supported output")
 	rootCmd.PersistentFlags().StringSlice("ignore-extension", nil, "List of file extensions to ignore")
 	rootCmd.PersistentFlags().StringSlice("ignore-path", nil, "List of file paths to ignore")
 	rootCmd.PersistentFlags().Bool("json", false, "output json format")
+	rootCmd.PersistentFlags().Bool("legacy-secret-id", false, "Generate the deprecated random SecretID for each finding")
 	rootCmd.PersistentFlags().Int("max-file-size", 10, "Max file size to scan (in MB)")
 	rootCmd.PersistentFlags().Int("num-threads", -1, "Number of execution threads")
+	rootCmd.PersistentFlags().StringSlice("only-signatures", nil, "Only scan with these signature IDs, or signatures with IDs or descriptions that match these glob patterns")
+	rootCmd.PersistentFlags().Int("redact-prefix", 4, "Number of characters to keep from the start of a redacted secret")
+	rootCmd.PersistentFlags().Bool("redact-secrets", false, "Only show the start and end of secrets in any supported output")
+	rootCmd.PersistentFlags().Int("redact-suffix", 4, "Number of characters to keep from the end of a redacted secret")
+	rootCmd.PersistentFlags().Bool("sarif", false, "output sarif format")
 	rootCmd.PersistentFlags().Bool("scan-tests", false, "Scan suspected test files")
+	rootCmd.PersistentFlags().Bool("since-last-scan", false, "Only scan commits added since the last successful scan of a repository")
 	rootCmd.PersistentFlags().String("signature-file", "$HOME/.wraith/signatures/default.yaml", "file(s) containing detection signatures.")
 	rootCmd.PersistentFlags().String("signature-path", "$HOME/.wraith/signatures", "path containing detection signatures.")
+	rootCmd.PersistentFlags().StringSlice("signature-tags", nil, "Only scan with signatures that have any of these tags, such as aws or cloud")
 	rootCmd.PersistentFlags().Bool("silent", false, "Suppress all output. An alternative output will need to be configured")
+	rootCmd.PersistentFlags().StringSlice("tags", nil, "Glob patterns of the tags to scan in addition to the default branch")
 	rootCmd.PersistentFlags().Bool("web-server", false, "Enable the web interface for scan output")
 
-	err := viper.BindPFlag("bind-address", rootCmd.PersistentFlags().Lookup("bind-address"))
+	err := viper.BindPFlag("all-refs", rootCmd.PersistentFlags().Lookup("all-refs"))
+	err = viper.BindPFlag("baseline", rootCmd.PersistentFlags().Lookup("baseline"))
+	err = viper.BindPFlag("bind-address", rootCmd.PersistentFlags().Lookup("bind-address"))
 	err = viper.BindPFlag("bind-port", rootCmd.PersistentFlags().Lookup("bind-port"))
+	err = viper.BindPFlag("branches", rootCmd.PersistentFlags().Lookup("branches"))
 	err = viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
+	err = viper.BindPFlag("dedupe-across-repos", rootCmd.PersistentFlags().Lookup("dedupe-across-repos"))
 	err = viper.BindPFlag("confidence-level", rootCmd.PersistentFlags().Lookup("confidence-level"))
 	err = viper.BindPFlag("config-file", rootCmd.PersistentFlags().Lookup("config-file"))
+	err = viper.BindPFlag("context-lines", rootCmd.PersistentFlags().Lookup("context-lines"))
 	err = viper.BindPFlag("csv", rootCmd.PersistentFlags().Lookup("csv"))
+	err = viper.BindPFlag("exclude-signature-tags", rootCmd.PersistentFlags().Lookup("exclude-signature-tags"))
+	err = viper.BindPFlag("exclude-signatures", rootCmd.PersistentFlags().Lookup("exclude-signatures"))
+	err = viper.BindPFlag("fail-on-confidence", rootCmd.PersistentFlags().Lookup("fail-on-confidence"))
+	err = viper.BindPFlag("fail-on-signature", rootCmd.PersistentFlags().Lookup("fail-on-signature"))
 	err = viper.BindPFlag("hide-secrets", rootCmd.PersistentFlags().Lookup("hide-secrets"))
 	err = viper.BindPFlag("ignore-extension", rootCmd.PersistentFlags().Lookup("ignore-extension"))
 	err = viper.BindPFlag("ignore-path", rootCmd.PersistentFlags().Lookup("ignore-path"))
 	err = viper.BindPFlag("json", rootCmd.PersistentFlags().Lookup("json"))
+	err = viper.BindPFlag("legacy-secret-id", rootCmd.PersistentFlags().Lookup("legacy-secret-id"))
 	err = viper.BindPFlag("max-file-size", rootCmd.PersistentFlags().Lookup("max-file-size"))
 	err = viper.BindPFlag("num-threads", rootCmd.PersistentFlags().Lookup("num-threads"))
+	err = viper.BindPFlag("only-signatures", rootCmd.PersistentFlags().Lookup("only-signatures"))
+	err = viper.BindPFlag("redact-prefix", rootCmd.PersistentFlags().Lookup("redact-prefix"))
+	err = viper.BindPFlag("redact-secrets", rootCmd.PersistentFlags().Lookup("redact-secrets"))
+	err = viper.BindPFlag("redact-suffix", rootCmd.PersistentFlags().Lookup("redact-suffix"))
+	err = viper.BindPFlag("sarif", rootCmd.PersistentFlags().Lookup("sarif"))
 	err = viper.BindPFlag("scan-tests", rootCmd.PersistentFlags().Lookup("scan-tests"))
+	err = viper.BindPFlag("since-last-scan", rootCmd.PersistentFlags().Lookup("since-last-scan"))
 	err = viper.BindPFlag("signature-file", rootCmd.PersistentFlags().Lookup("signature-file"))
 	err = viper.BindPFlag("signature-path", rootCmd.PersistentFlags().Lookup("signature-path"))
+	err = viper.BindPFlag("signature-tags", rootCmd.PersistentFlags().Lookup("signature-tags"))
 	err = viper.BindPFlag("silent", rootCmd.PersistentFlags().Lookup("silent"))
+	err = viper.BindPFlag("tags", rootCmd.PersistentFlags().Lookup("tags"))
 	err = viper.BindPFlag("web-server", rootCmd.PersistentFlags().Lookup("web-server"))
 
 	if err != nil {
@@ -0,0 +1,87 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// scanBitbucketCmd represents the scanBitbucket command
+var scanBitbucketCmd = &cobra.Command{
+	Use:   "scanBitbucket",
+	Short: "Scan one or more Bitbucket Cloud workspaces for secrets",
+	Long:  "Scan one or more Bitbucket Cloud workspaces for secrets",
+	Run: func(cmd *cobra.Command, args []string) {
+		runBitbucketScan("bitbucket", func(sess *core.Session) string {
+			if len(sess.BitbucketTargets) == 0 {
+				return "You need to specify one or more workspaces to scan with --bitbucket-workspaces.\n"
+			}
+			return ""
+		})
+	},
+}
+
+// runBitbucketScan will scan Bitbucket Cloud or Bitbucket Server. The check returns the error to give the user
+// when the flags the command needs are missing, or an empty string when they are all there.
+func runBitbucketScan(scanType string, check func(sess *core.Session) string) {
+	sess := core.NewSession(scanType)
+
+	// By default we display a header to the user giving basic info about application. This will not be displayed
+	// during a silent run which is the default when using this in an automated fashion.
+	if !sess.JSONOutput && !sess.CSVOutput && !sess.SARIFOutput {
+		sess.Out.Warn("%s\n\n", core.ASCIIBanner)
+		sess.Out.Important("%s v%s started at %s\n", core.Name, sess.WraithVersion, sess.Stats.StartedAt.Format(time.RFC3339))
+		sess.Out.Important("Loaded %d signatures.\n", len(core.Signatures))
+		if sess.WebServer {
+			sess.Out.Important("Web interface available at http://%s:%d\n", sess.BindAddress, sess.BindPort)
+		}
+	}
+
+	if msg := check(sess); msg != "" {
+		sess.Out.Error(msg)
+		os.Exit(core.ExitCodeError)
+	}
+
+	sess.InitGitClient()
+
+	core.GatherTargets(sess)
+	core.GatherGitlabRepositories(sess)
+	core.AnalyzeRepositories(sess)
+	sess.Finish()
+
+	core.SummaryOutput(sess)
+
+	if !sess.Silent && sess.WebServer {
+		sess.Out.Important("Press Ctrl+C to stop web server and exit.\n")
+		select {}
+	}
+
+	os.Exit(sess.ExitCode)
+}
+
+func init() {
+	rootCmd.AddCommand(scanBitbucketCmd)
+
+	scanBitbucketCmd.Flags().Bool("add-org-members", false, "Add members to targets when processing workspaces")
+	scanBitbucketCmd.Flags().String("bitbucket-api-token", "", "Access token or app password for Bitbucket, see doc for necessary scope")
+	scanBitbucketCmd.Flags().String("bitbucket-username", "", "Username to use with an app password, leave empty for an access token")
+	scanBitbucketCmd.Flags().StringSlice("bitbucket-workspaces", nil, "List of Bitbucket workspaces to scan")
+	scanBitbucketCmd.Flags().Float64("commit-depth", -1, "Set the commit depth to scan")
+
+	err := viper.BindPFlag("add-org-members", scanBitbucketCmd.Flags().Lookup("add-org-members"))
+	err = viper.BindPFlag("bitbucket-api-token", scanBitbucketCmd.Flags().Lookup("bitbucket-api-token"))
+	err = viper.BindPFlag("bitbucket-username", scanBitbucketCmd.Flags().Lookup("bitbucket-username"))
+	err = viper.BindPFlag("bitbucket-workspaces", scanBitbucketCmd.Flags().Lookup("bitbucket-workspaces"))
+	err = viper.BindPFlag("commit-depth", scanBitbucketCmd.Flags().Lookup("commit-depth"))
+
+	if err != nil {
+		fmt.Printf("There was an error binding a flag: %s\n", err.Error())
+	}
+}
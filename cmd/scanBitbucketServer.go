@@ -0,0 +1,52 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// scanBitbucketServerCmd represents the scanBitbucketServer command
+var scanBitbucketServerCmd = &cobra.Command{
+	Use:   "scanBitbucketServer",
+	Short: "Scan one or more Bitbucket Server projects or users for secrets",
+	Long:  "Scan one or more Bitbucket Server projects or users for secrets",
+	Run: func(cmd *cobra.Command, args []string) {
+		runBitbucketScan("bitbucket-server", func(sess *core.Session) string {
+			if sess.BitbucketURL == "" {
+				return "You need to specify the url of your Bitbucket Server with --bitbucket-server-url.\n"
+			}
+			if len(sess.BitbucketTargets) == 0 {
+				return "You need to specify one or more projects or users to scan with --bitbucket-server-projects.\n"
+			}
+			return ""
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scanBitbucketServerCmd)
+
+	scanBitbucketServerCmd.Flags().Bool("add-org-members", false, "Add users with access to targets when processing projects")
+	scanBitbucketServerCmd.Flags().String("bitbucket-server-api-token", "", "HTTP access token or password for Bitbucket Server, see doc for necessary scope")
+	scanBitbucketServerCmd.Flags().StringSlice("bitbucket-server-projects", nil, "List of Bitbucket Server project keys or users to scan")
+	scanBitbucketServerCmd.Flags().String("bitbucket-server-url", "", "Url of the Bitbucket Server, ex. https://bitbucket.example.com")
+	scanBitbucketServerCmd.Flags().String("bitbucket-server-username", "", "Username to use with the token, leave empty to use it as a bearer token")
+	scanBitbucketServerCmd.Flags().Float64("commit-depth", -1, "Set the commit depth to scan")
+
+	err := viper.BindPFlag("add-org-members", scanBitbucketServerCmd.Flags().Lookup("add-org-members"))
+	err = viper.BindPFlag("bitbucket-server-api-token", scanBitbucketServerCmd.Flags().Lookup("bitbucket-server-api-token"))
+	err = viper.BindPFlag("bitbucket-server-projects", scanBitbucketServerCmd.Flags().Lookup("bitbucket-server-projects"))
+	err = viper.BindPFlag("bitbucket-server-url", scanBitbucketServerCmd.Flags().Lookup("bitbucket-server-url"))
+	err = viper.BindPFlag("bitbucket-server-username", scanBitbucketServerCmd.Flags().Lookup("bitbucket-server-username"))
+	err = viper.BindPFlag("commit-depth", scanBitbucketServerCmd.Flags().Lookup("commit-depth"))
+
+	if err != nil {
+		fmt.Printf("There was an error binding a flag: %s\n", err.Error())
+	}
+}
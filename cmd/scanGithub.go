@@ -36,7 +36,7 @@ var scanGithubCmd = &cobra.Command{
 
 		// By default we display a header to the user giving basic info about application. This will not be displayed
 		// during a silent run which is the default when using this in an automated fashion.
-		if !sess.JSONOutput && !sess.CSVOutput {
+		if !sess.JSONOutput && !sess.CSVOutput && !sess.SARIFOutput {
 			sess.Out.Warn("%s\n\n", core.ASCIIBanner)
 			sess.Out.Important("%s v%s started at %s\n", core.Name, sess.WraithVersion, sess.Stats.StartedAt.Format(time.RFC3339))
 			sess.Out.Important("Loaded %d signatures.\n", len(core.Signatures))
@@ -85,7 +85,12 @@ var scanGithubCmd = &cobra.Command{
 			// Catchall for not being able to scan any as either we have no information or
 			// we don't have the rights kinds of information
 			sess.Out.Error("You need to specify an org or user that contains the repo(s).\n")
-			os.Exit(1)
+			os.Exit(core.ExitCodeError)
+		}
+
+		// Gists belong to the users gathered above so they need to be gathered last
+		if sess.ScanGists {
+			core.GatherGists(sess)
 		}
 
 		core.AnalyzeRepositories(sess)
@@ -97,6 +102,8 @@ var scanGithubCmd = &cobra.Command{
 			sess.Out.Important("Press Ctrl+C to stop web server and exit.\n")
 			select {}
 		}
+
+		os.Exit(sess.ExitCode)
 	},
 }
 
@@ -109,6 +116,8 @@ func init() {
 	scanGithubCmd.Flags().StringSlice("github-orgs", nil, "List of github orgs to scan")
 	scanGithubCmd.Flags().StringSlice("github-repos", nil, "List of github repositories to scan")
 	scanGithubCmd.Flags().StringSlice("github-users", nil, "List of github.com users to scan")
+	scanGithubCmd.Flags().Bool("scan-gists", false, "Scan the gists of the users and org members being scanned")
+	scanGithubCmd.Flags().Bool("scan-pull-requests", false, "Scan the commits of open and closed pull requests, including ones that were never merged")
 
 	err := viper.BindPFlag("add-org-members", scanGithubCmd.Flags().Lookup("add-org-members"))
 	err = viper.BindPFlag("github-api-token", scanGithubCmd.Flags().Lookup("github-api-token"))
@@ -34,7 +34,7 @@ var scanGithubEnterpriseCmd = &cobra.Command{
 
 		// By default we display a header to the user giving basic info about application. This will not be displayed
 		// during a silent run which is the default when using this in an automated fashion.
-		if !sess.JSONOutput && !sess.CSVOutput {
+		if !sess.JSONOutput && !sess.CSVOutput && !sess.SARIFOutput {
 			sess.Out.Warn("%s\n\n", core.ASCIIBanner)
 			sess.Out.Important("%s v%s started at %s\n", core.Name, sess.WraithVersion, sess.Stats.StartedAt.Format(time.RFC3339))
 			sess.Out.Important("Loaded %d signatures.\n", len(core.Signatures))
@@ -78,10 +78,15 @@ var scanGithubEnterpriseCmd = &cobra.Command{
 				core.GatherGithubRepositoriesFromOwner(sess)
 			} else {
 				sess.Out.Error("You need to specify an org or user that contains the repo(s).\n")
-				os.Exit(1)
+				os.Exit(core.ExitCodeError)
 			}
 		}
 
+		// Gists belong to the users gathered above so they need to be gathered last
+		if sess.ScanGists {
+			core.GatherGists(sess)
+		}
+
 		core.AnalyzeRepositories(sess)
 		sess.Finish()
 
@@ -91,6 +96,8 @@ var scanGithubEnterpriseCmd = &cobra.Command{
 			sess.Out.Important("Press Ctrl+C to stop web server and exit.\n")
 			select {}
 		}
+
+		os.Exit(sess.ExitCode)
 	},
 }
 
@@ -104,6 +111,8 @@ func init() {
 	scanGithubEnterpriseCmd.Flags().StringSlice("github-enterprise-repos", nil, "List of github repositories to scan")
 	scanGithubEnterpriseCmd.Flags().String("github-enterprise-url", "", "Entperise Github instance. I.E. https://github.org.com")
 	scanGithubEnterpriseCmd.Flags().StringSlice("github-enterprise-users", nil, "List of github.com users to scan")
+	scanGithubEnterpriseCmd.Flags().Bool("scan-gists", false, "Scan the gists of the users and org members being scanned")
+	scanGithubEnterpriseCmd.Flags().Bool("scan-pull-requests", false, "Scan the commits of open and closed pull requests, including ones that were never merged")
 
 	err := viper.BindPFlag("add-org-members", scanGithubEnterpriseCmd.Flags().Lookup("add-org-members"))
 	err = viper.BindPFlag("commit-depth", scanGithubEnterpriseCmd.Flags().Lookup("commit-depth"))
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/cobra"
+)
+
+// runSession will run wraith with the given arguments, replacing the run of the command with one that only
+// creates the session so no scan is started
+func runSession(c *cobra.Command, scanType string, args ...string) *core.Session {
+	var sess *core.Session
+	run := c.Run
+	defer func() { c.Run = run }()
+	c.Run = func(cmd *cobra.Command, args []string) {
+		sess = core.NewSession(scanType)
+	}
+	rootCmd.SetArgs(args)
+	_ = rootCmd.Execute()
+	return sess
+}
+
+func TestScanGithubFlags(t *testing.T) {
+
+	Convey("Given the github commands", t, func() {
+		home, _ := ioutil.TempDir("", "wraith")
+		defer os.RemoveAll(home)
+		defer os.Setenv("HOME", os.Getenv("HOME"))
+		_ = os.Setenv("HOME", home)
+
+		Convey("When scanGithub is run with --scan-gists", func() {
+			sess := runSession(scanGithubCmd, "github", "scanGithub", "--scan-gists")
+
+			Convey("Gists should be scanned", func() {
+				So(sess.ScanGists, ShouldBeTrue)
+			})
+		})
+
+		Convey("When scanGithub is run with --scan-pull-requests", func() {
+			sess := runSession(scanGithubCmd, "github", "scanGithub", "--scan-pull-requests")
+
+			Convey("Pull requests should be scanned", func() {
+				So(sess.ScanPullRequests, ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -4,6 +4,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/N0MoreSecr3ts/wraith/core"
@@ -24,7 +25,7 @@ var scanGitlabCmd = &cobra.Command{
 
 		// By default we display a header to the user giving basic info about application. This will not be displayed
 		// during a silent run which is the default when using this in an automated fashion.
-		if !sess.JSONOutput && !sess.CSVOutput {
+		if !sess.JSONOutput && !sess.CSVOutput && !sess.SARIFOutput {
 			sess.Out.Warn("%s\n\n", core.ASCIIBanner)
 			sess.Out.Important("%s v%s started at %s\n", core.Name, sess.WraithVersion, sess.Stats.StartedAt.Format(time.RFC3339))
 			sess.Out.Important("Loaded %d signatures.\n", len(core.Signatures))
@@ -45,6 +46,12 @@ var scanGitlabCmd = &cobra.Command{
 
 		core.GatherTargets(sess)
 		core.GatherGitlabRepositories(sess)
+
+		// Project snippets belong to the projects gathered above so they need to be gathered last
+		if sess.ScanSnippets {
+			core.GatherGitlabSnippets(sess)
+		}
+
 		core.AnalyzeRepositories(sess)
 		sess.Finish()
 
@@ -55,6 +62,8 @@ var scanGitlabCmd = &cobra.Command{
 			sess.Out.Important("Press Ctrl+C to stop web server and exit.\n")
 			select {}
 		}
+
+		os.Exit(sess.ExitCode)
 	},
 }
 
@@ -65,11 +74,15 @@ func init() {
 	scanGitlabCmd.Flags().Float64("commit-depth", -1, "Set the commit depth to scan")
 	scanGitlabCmd.Flags().String("gitlab-api-token", "", "API token for access to gitlab, see doc for necessary scope")
 	scanGitlabCmd.Flags().StringSlice("gitlab-projects", nil, "List of Gitlab projects or users to scan")
+	scanGitlabCmd.Flags().String("gitlab-url", core.GitLabBaseURL, "Url of the GitLab instance to scan, ex. https://gitlab.example.com")
+	scanGitlabCmd.Flags().Bool("scan-snippets", false, "Scan the snippets of projects being scanned and the personal snippets of the token owner")
 
 	err := viper.BindPFlag("add-org-members", scanGitlabCmd.Flags().Lookup("add-org-members"))
 	err = viper.BindPFlag("commit-depth", scanGitlabCmd.Flags().Lookup("commit-depth"))
 	err = viper.BindPFlag("gitlab-api-token", scanGitlabCmd.Flags().Lookup("gitlab-api-token"))
 	err = viper.BindPFlag("gitlab-projects", scanGitlabCmd.Flags().Lookup("gitlab-projects"))
+	err = viper.BindPFlag("gitlab-url", scanGitlabCmd.Flags().Lookup("gitlab-url"))
+	err = viper.BindPFlag("scan-snippets", scanGitlabCmd.Flags().Lookup("scan-snippets"))
 
 	if err != nil {
 		fmt.Printf("There was an error binding a flag: %s\n", err.Error())
@@ -4,6 +4,7 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/N0MoreSecr3ts/wraith/core"
@@ -32,7 +33,7 @@ var scanLocalPathCmd = &cobra.Command{
 
 		// By default we display a header to the user giving basic info about application. This will not be displayed
 		// during a silent run which is the default when using this in an automated fashion.
-		if !sess.JSONOutput && !sess.CSVOutput {
+		if !sess.JSONOutput && !sess.CSVOutput && !sess.SARIFOutput {
 			sess.Out.Warn("%s\n\n", core.ASCIIBanner)
 			sess.Out.Important("%s v%s started at %s\n", core.Name, sess.WraithVersion, sess.Stats.StartedAt.Format(time.RFC3339))
 			sess.Out.Important("Loaded %d signatures.\n", len(core.Signatures))
@@ -47,7 +48,7 @@ var scanLocalPathCmd = &cobra.Command{
 				if last == "/" {
 					core.ScanDir(p, sess)
 				} else {
-					core.DoFileScan(p, sess)
+					core.DoFileScan(p, nil, sess)
 				}
 			}
 		}
@@ -62,6 +63,8 @@ var scanLocalPathCmd = &cobra.Command{
 			select {}
 		}
 
+		os.Exit(sess.ExitCode)
+
 	},
 }
 
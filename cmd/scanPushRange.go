@@ -0,0 +1,53 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package cmd
+
+import (
+	"os"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// scanPushRangeCmd represents the scanPushRange command
+var scanPushRangeCmd = &cobra.Command{
+	Use:   "scanPushRange",
+	Short: "Scan the commits of a push to a bare repo, for use as a pre-receive hook",
+	Long: "Scan the commits of a push to a bare repo, for use as a pre-receive hook. The 'oldrev newrev refname' lines " +
+		"that git passes to the hook are read from stdin and the push is rejected if any secrets are found.",
+	Run: func(cmd *cobra.Command, args []string) {
+
+		scanType := "pushRange"
+		sess := core.NewSession(scanType)
+
+		updates, err := core.ParsePushUpdates(os.Stdin)
+		if err != nil {
+			sess.Out.Fatal("Unable to read the pushed refs: %s\n", err)
+		}
+
+		rejections := core.ScanPushRange(viper.GetString("repo-path"), updates, sess)
+		sess.Finish()
+
+		// The person pushing only needs to see what was found, the full summary is only given for the
+		// machine readable formats
+		if sess.JSONOutput || sess.CSVOutput || sess.SARIFOutput {
+			core.SummaryOutput(sess)
+		} else {
+			sess.ExitCode = sess.EvaluateExitCode(core.Signatures)
+			if sess.ExitCode == core.ExitCodeFindings {
+				core.PushRejectionOutput(rejections, sess)
+			}
+		}
+
+		// Reject the push if anything was found
+		os.Exit(sess.ExitCode)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scanPushRangeCmd)
+
+	scanPushRangeCmd.Flags().String("repo-path", ".", "Path to the bare git repo the push is being made to")
+}
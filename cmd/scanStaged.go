@@ -0,0 +1,47 @@
+// Package cmd represents the specific commands that the user will execute. Only specific code related to the command
+// should be in these files. As much of the code as possible should be pushed to other packages.
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// scanStagedCmd represents the scanStaged command
+var scanStagedCmd = &cobra.Command{
+	Use:   "scanStaged",
+	Short: "Scan the staged changes of a local git repo, for use as a pre-commit hook",
+	Long:  "Scan the staged changes of a local git repo, for use as a pre-commit hook. Exits non-zero if any secrets are found.",
+	Run: func(cmd *cobra.Command, args []string) {
+
+		scanType := "staged"
+		sess := core.NewSession(scanType)
+
+		// By default we display a header to the user giving basic info about application. This will not be displayed
+		// during a silent run which is the default when using this in an automated fashion.
+		if !sess.JSONOutput && !sess.CSVOutput && !sess.SARIFOutput {
+			sess.Out.Warn("%s\n\n", core.ASCIIBanner)
+			sess.Out.Important("%s v%s started at %s\n", core.Name, sess.WraithVersion, sess.Stats.StartedAt.Format(time.RFC3339))
+			sess.Out.Important("Loaded %d signatures.\n", len(core.Signatures))
+		}
+
+		core.ScanStaged(viper.GetString("repo-path"), sess)
+		sess.Finish()
+
+		core.SummaryOutput(sess)
+
+		// Stop the commit if anything was found
+		os.Exit(sess.ExitCode)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scanStagedCmd)
+
+	scanStagedCmd.Flags().String("repo-path", ".", "Path to the local git repo whose staged changes will be scanned")
+}
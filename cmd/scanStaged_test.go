@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+)
+
+func TestRepoPathFlag(t *testing.T) {
+
+	Convey("Given the commands that scan a local repo", t, func() {
+		home, _ := ioutil.TempDir("", "wraith")
+		defer os.RemoveAll(home)
+		defer os.Setenv("HOME", os.Getenv("HOME"))
+		_ = os.Setenv("HOME", home)
+
+		Convey("When scanStaged is run with --repo-path", func() {
+			runSession(scanStagedCmd, "staged", "scanStaged", "--repo-path", "/srv/staged")
+
+			Convey("The repo path should be the one given", func() {
+				So(viper.GetString("repo-path"), ShouldEqual, "/srv/staged")
+			})
+		})
+
+		Convey("When scanPushRange is run with --repo-path", func() {
+			runSession(scanPushRangeCmd, "pushRange", "scanPushRange", "--repo-path", "/srv/repo.git")
+
+			Convey("The repo path should be the one given", func() {
+				So(viper.GetString("repo-path"), ShouldEqual, "/srv/repo.git")
+			})
+		})
+
+		Convey("When a command that does not scan a local repo is looked at", func() {
+			Convey("It should not have a repo path flag", func() {
+				So(scanGithubCmd.Flags().Lookup("repo-path"), ShouldBeNil)
+			})
+		})
+	})
+}
@@ -24,7 +24,7 @@ func cleanInput(u string) string {
 
 	if err != nil {
 		fmt.Println(err)
-		os.Exit(2)
+		os.Exit(core.ExitCodeError)
 	}
 
 	return u
@@ -53,7 +53,7 @@ func fetchSignatures(sess *core.Session) string {
 	dir, err := ioutil.TempDir("", "wraith")
 	if err != nil {
 		fmt.Println(err)
-		os.Exit(2)
+		os.Exit(core.ExitCodeError)
 	}
 
 	// for now we only pull from a given version at some point we can look at pulling the latest
@@ -95,7 +95,7 @@ func fetchSignatures(sess *core.Session) string {
 		})
 		if err != nil {
 			fmt.Println("Requested version not available. Please enter a valid version")
-			os.Exit(2)
+			os.Exit(core.ExitCodeError)
 		}
 	}
 	return dir
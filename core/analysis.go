@@ -6,6 +6,10 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
 )
 
 // GatherTargets will enumerate git targets adding them to a running target list. This will set the targets based
@@ -18,12 +22,12 @@ func GatherTargets(sess *Session) {
 
 	// Based on the type of scan, set in the cmd package, we set a generic
 	// variable to the specific targets
-	//switch sess.ScanType {
-	//case "github":
-	//	targets = sess.GithubTargets
-	//case "gitlab":
-	targets = sess.GitlabTargets
-	//}
+	switch sess.ScanType {
+	case "bitbucket", "bitbucket-server":
+		targets = sess.BitbucketTargets
+	default:
+		targets = sess.GitlabTargets
+	}
 
 	//var target *Owner
 
@@ -124,17 +128,59 @@ func AnalyzeRepositories(sess *Session) {
 				// If we have cloned the repository successfully then we can increment the count
 				sess.Stats.IncrementRepositoriesCloned()
 
-				// Get the full commit history for the repo
-				history, err := GetRepositoryHistory(clone)
+				// Get the refs whose history is scanned, which is only HEAD unless more refs were asked for
+				var refs []plumbing.Hash
+				var pullRequests map[plumbing.Hash]int
+				if sess.scansAllRefs() || sess.ScanPullRequests {
+					refs, err = GetScanRefs(clone, sess.AllRefs, sess.Branches, sess.Tags)
+					if err == nil && sess.ScanPullRequests {
+						pullRequests, err = getPullRequestHistory(clone, &refs)
+					}
+					if err != nil {
+						sess.Out.Error("[THREAD #%d][%s] Error getting the refs to scan: %s\n", tid, *repo.CloneURL, err)
+						sess.Stats.IncrementScanFailures()
+						_ = os.RemoveAll(path)
+						continue
+					}
+					sess.Out.Debug("[THREAD #%d][%s] Number of refs: %d\n", tid, *repo.CloneURL, len(refs))
+				}
+
+				// Get the full commit history for the repo, or only the commits since the last scan
+				var cursor []string
+				if sess.SinceLastScan {
+					cursor = sess.State.Commits(stateKey(sess, repo))
+				}
+				history, usedCursor, err := GetRepositoryHistorySince(clone, cursor, refs...)
 				if err != nil {
 					sess.Out.Error("[THREAD #%d][%s] Error getting commit history: %s\n", tid, *repo.CloneURL, err)
-					err := os.RemoveAll(path)
-					sess.Out.Error("[THREAD #%d][%s] Error removing path from disk: %s\n", tid, *repo.CloneURL, err)
+					sess.Stats.IncrementScanFailures()
+					if err := os.RemoveAll(path); err != nil {
+						sess.Out.Error("[THREAD #%d][%s] Error removing path from disk: %s\n", tid, *repo.CloneURL, err)
+					}
 					continue
 				}
 
+				if len(cursor) > 0 && !usedCursor {
+					sess.Out.Warn("[THREAD #%d][%s] Last scanned commits %s not found, scanning the full history\n", tid, *repo.CloneURL, strings.Join(cursor, ", "))
+				}
+
 				sess.Out.Debug("[THREAD #%d][%s] Number of commits: %d\n", tid, *repo.CloneURL, len(history))
 
+				// Load any paths, signatures or secrets the repo has marked as known false positives
+				ignore := loadRepoWraithIgnore(clone)
+
+				// Blobs that have already been scanned are only matched once, and the findings from each blob are
+				// kept so later commits with the same blob can be added to them
+				blobCache := sess.BlobCache
+				if blobCache == nil {
+					blobCache = NewBlobCache()
+				}
+				blobFindings := make(map[BlobKey][]*Finding)
+				var repoFindings []*Finding
+
+				// The cursor is not moved past commits that could not be scanned so they are scanned again next time
+				failed := false
+
 				// Add in the commits found to the repo into the running total of all commits found
 				sess.Stats.CommitsTotal = sess.Stats.CommitsTotal + len(history)
 
@@ -143,7 +189,10 @@ func AnalyzeRepositories(sess *Session) {
 				// recent path. The does not do a fetch per history so if a file changes paths from
 				// the current one it will throw a file not found error. You can see this by turning
 				// on debugging.
-				for _, commit := range history {
+				// The history is walked from the oldest commit so a finding is reported at the commit it was
+				// introduced in, with any later commits containing the same change added to it.
+				for i := len(history) - 1; i >= 0; i-- {
+					commit := history[i]
 
 					sess.Out.Debug("[THREAD #%d][%s] Analyzing commit: %s\n", tid, *repo.CloneURL, commit.Hash)
 
@@ -157,7 +206,13 @@ func AnalyzeRepositories(sess *Session) {
 					// through the commit history of a given repo.
 					dirtyCommit := false
 
-					changes, _ := GetChanges(commit, clone)
+					changes, err := GetChanges(commit, clone)
+					if err != nil {
+						sess.Out.Error("[THREAD #%d][%s] Error getting the changes of commit %s: %s\n", tid, *repo.CloneURL, commit.Hash, err)
+						sess.Stats.IncrementScanFailures()
+						failed = true
+						continue
+					}
 					sess.Out.Debug("[THREAD #%d][%s] %d changes in %s\n", tid, *repo.CloneURL, len(changes), commit.Hash)
 
 					for _, change := range changes {
@@ -208,6 +263,14 @@ func AnalyzeRepositories(sess *Session) {
 							continue
 						}
 
+						// If the repo has ignored the path in its .wraithignore we pass on through
+						if ignore.IsIgnoredPath(fPath) {
+							sess.Stats.IncrementFilesIgnored()
+							sess.Out.Debug("[THREAD #%d][%s] %s is in %s and being ignored\n", tid, *repo.CloneURL, fPath, WraithIgnoreFile)
+
+							continue
+						}
+
 						// Break a file name up into its composite pieces including the extension and base name
 						matchFile := newMatchFile(fullFilePath)
 
@@ -220,77 +283,117 @@ func AnalyzeRepositories(sess *Session) {
 							continue
 						}
 
-						// We are now finally at the point where we are going to scan a file so we implement
-						// that count.
-						sess.Stats.IncrementFilesScanned()
+						// The same blob was already scanned in an earlier commit, such as a merge bringing in the
+						// commits of a branch or a file that was copied, so the findings from it are also present in
+						// this commit. A deleted file has no blob and is always scanned.
+						key, dedupe := NewBlobKey(change)
+						if findings, ok := blobFindings[key]; dedupe && ok {
+							sess.Stats.IncrementFilesDeduplicated()
+							for _, finding := range findings {
+								sess.AddFindingCommit(finding, commit.Hash.String())
+							}
+							if len(findings) > 0 {
+								dirtyCommit = true
+							}
+							continue
+						}
+
+						// The blob may have already been matched in another repository when the cache is shared
+						var matches []BlobMatch
+						cached := false
+						if dedupe {
+							matches, cached = blobCache.Get(key)
+						}
+						if cached {
+							sess.Stats.IncrementFilesDeduplicated()
+						} else {
+							// We are now finally at the point where we are going to scan a file so we implement
+							// that count.
+							sess.Stats.IncrementFilesScanned()
+
+							// The lines added by the change are worked out once for every signature, and a single
+							// pass for the keywords of the signatures picks out the ones that may match them
+							var keywords map[string]bool
+							if err := matchFile.loadAdditions(change); err != nil {
+								sess.Out.Error("[THREAD #%d][%s] Error getting the changes to %s in commit %s: %s\n", tid, *repo.CloneURL, fPath, commit.Hash, err)
+								sess.Stats.IncrementScanFailures()
+								failed = true
+								continue
+							}
+							if SignatureKeywords != nil {
+								keywords = SignatureKeywords.Find(matchFile.addedContent())
+							}
+
+							// for each signature that is loaded scan the file as a whole and generate a map of
+							// the match and the line number the match was found on
+							for _, signature := range Signatures {
+								if !SignatureKeywords.MayMatch(signature, keywords) {
+									continue
+								}
+								bMatched, matchMap := signature.ExtractMatch(matchFile, sess, change)
+								if bMatched {
+									matches = append(matches, BlobMatch{Signature: signature, Matches: matchMap})
+								}
+							}
+							if dedupe {
+								blobCache.Add(key, matches)
+							}
+						}
 
 						// We set this to a default of fale and will be used at the end of matching to
 						// increment the file count. If we try and do this in the loop it will hit for every
 						// signature and give us a false count.
-						dirtyFile := false
-
-						// for each signature that is loaded scan the file as a whole and generate a map of
-						// the match and the line number the match was found on
-						for _, signature := range Signatures {
+						dirtyFile := len(matches) > 0
+						if dirtyFile {
+							dirtyCommit = true
+						}
 
-							bMatched, matchMap := signature.ExtractMatch(matchFile, sess, change)
-							if bMatched {
+						var added []*Finding
+						for _, match := range matches {
+							signature := match.Signature
+							matchMap := match.Matches
+
+							// For every instance of the secret that matched the specific signatures
+							// create a new finding. Identical changes in later commits are added to
+							// the commits of these findings rather than creating new ones.
+							for k, v := range matchMap {
+
+								// Create a new instance of a finding and set the necessary fields.
+								finding := newFinding(sess, signature, k, v, fPath, changeAction, commit)
+								finding.RepositoryName = *repo.Name
+								finding.RepositoryOwner = *repo.Owner
+								if repo.Gist {
+									finding.gistURL = *repo.URL
+								} else if repo.Snippet {
+									finding.snippetURL = *repo.URL
+								}
+								if n, ok := pullRequests[commit.Hash]; ok {
+									finding.PullRequest = strconv.Itoa(n)
+								}
+								// Set the urls and the fingerprint for the finding
+								finding.Initialize(sess)
 
-								dirtyFile = true
-								dirtyCommit = true
+								// The signature or the secret has been allowed by the repo
+								if ignore.IsIgnoredFinding(finding) {
+									sess.Stats.IncrementFindingsSuppressed()
+									continue
+								}
 
-								// content will hold the secret found within the target
-								var content string
-
-								// For every instance of the secret that matched the specific signatures
-								// create a new finding. Thi will produce dupes as the file may exist
-								// in multiple commits.
-								for k, v := range matchMap {
-
-									// This sets the content for the finding, in this case the actual secret
-									// is the content. This can be removed and hidden via a commandline flag.
-									cleanK := strings.SplitAfterN(k, "_", 2)
-									if matchMap == nil {
-										content = ""
-									} else {
-										content = cleanK[1]
-
-									}
-
-									// Destroy the secret by zeroing the content if the flag is set
-									if sess.HideSecrets {
-										content = ""
-									}
-
-									// Create a new instance of a finding and set the necessary fields.
-									finding := &Finding{
-										Action:           changeAction,
-										Content:          content,
-										CommitAuthor:     commit.Author.String(),
-										CommitHash:       commit.Hash.String(),
-										CommitMessage:    strings.TrimSpace(commit.Message),
-										Description:      signature.Description(),
-										FilePath:         fPath,
-										WraithVersion:    sess.WraithVersion,
-										LineNumber:       strconv.Itoa(v),
-										RepositoryName:   *repo.Name,
-										RepositoryOwner:  *repo.Owner,
-										SignatureID:      signature.SignatureID(),
-										signatureVersion: sess.SignatureVersion,
-										SecretID:         generateID(),
-									}
-									// Set the urls for the finding
-									finding.Initialize(sess)
-
-									// Add it to the session
-									sess.AddFinding(finding)
-									sess.Out.Debug("[THREAD #%d][%s] Done analyzing changes in %s\n", tid, *repo.CloneURL, commit.Hash)
-
-									// Print realtime data to stdout
-									realTimeOutput(finding, sess)
+								// Add it to the session, skipping it if we already have the exact same finding
+								if !sess.AddFinding(finding) {
+									continue
 								}
+								added = append(added, finding)
+								repoFindings = append(repoFindings, finding)
+								sess.Out.Debug("[THREAD #%d][%s] Done analyzing changes in %s\n", tid, *repo.CloneURL, commit.Hash)
+
+								// Print realtime data to stdout
+								realTimeOutput(finding, sess)
 							}
 						}
+						if dedupe {
+							blobFindings[key] = added
+						}
 						if dirtyFile {
 							sess.Out.Debug("this is the file getting added: %s \n", fullFilePath)
 							sess.Stats.IncrementFilesDirty()
@@ -303,6 +406,20 @@ func AnalyzeRepositories(sess *Session) {
 				}
 
 				sess.Out.Debug("[THREAD #%d][%s] Done analyzing commits\n", tid, *repo.CloneURL)
+
+				// Work out when each secret was introduced and removed now that the whole history has been seen. This
+				// can not be worked out from the commits since the last scan, which are only part of the history.
+				setSecretLifecycle(sess, clone, history, repoFindings, usedCursor)
+				forgetSecrets(repoFindings)
+
+				// Now that the repo has been fully analyzed we can move the cursor up to the refs that were scanned
+				if sess.SinceLastScan {
+					if failed {
+						sess.Out.Warn("[THREAD #%d][%s] Some commits could not be scanned, the last scanned commits are not updated\n", tid, *repo.CloneURL)
+					} else {
+						updateScanCursor(sess, repo, clone, refs)
+					}
+				}
 				sess.Out.Debug("[THREAD #%d][%s] Deleted %s\n", tid, *repo.CloneURL, path)
 
 				err = os.RemoveAll(path)
@@ -321,3 +438,49 @@ func AnalyzeRepositories(sess *Session) {
 	wg.Wait()
 
 }
+
+// getPullRequestHistory will add the heads of the pull requests to the refs being scanned, after the other refs,
+// and return the pull request that each commit only reachable from a pull request belongs to.
+func getPullRequestHistory(clone *git.Repository, refs *[]plumbing.Hash) (map[plumbing.Hash]int, error) {
+	heads, err := GetPullRequestRefs(clone)
+	if err != nil {
+		return nil, err
+	}
+	pullRequests := GetPullRequestCommits(clone, *refs, heads)
+	for head := range heads {
+		*refs = append(*refs, head)
+	}
+	return pullRequests, nil
+}
+
+// updateScanCursor will save the HEAD of a repository as the last scanned commit for that repository and branch,
+// along with the tips of the refs that were scanned so the commits that are only on them are not scanned again
+func updateScanCursor(sess *Session, repo *Repository, clone *git.Repository, refs []plumbing.Hash) {
+	head, err := clone.Head()
+	if err != nil {
+		sess.Out.Error("Unable to update the last scanned commit for %s: %s\n", *repo.CloneURL, err)
+		return
+	}
+
+	commits := []string{head.Hash().String()}
+	seen := map[plumbing.Hash]bool{head.Hash(): true}
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			commits = append(commits, ref.String())
+		}
+	}
+
+	sess.State.SetCursor(stateKey(sess, repo), &ScanCursor{
+		RepositoryID:   *repo.ID,
+		RepositoryName: *repo.FullName,
+		Branch:         *repo.DefaultBranch,
+		CommitHash:     head.Hash().String(),
+		Commits:        commits,
+		ScannedAt:      time.Now(),
+	})
+
+	if err := sess.State.Save(); err != nil {
+		sess.Out.Error("Unable to save the scan state: %s\n", err)
+	}
+}
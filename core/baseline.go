@@ -0,0 +1,118 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// BaselineEntry is a single known finding. The secret itself is never stored so a baseline can safely
+// be committed alongside the code it covers.
+type BaselineEntry struct {
+	Fingerprint    string
+	SignatureID    string
+	Description    string
+	FilePath       string
+	LineNumber     string
+	RepositoryName string
+	CommitHash     string
+}
+
+// Baseline holds a set of findings that have already been triaged and should not be reported again
+type Baseline struct {
+	WraithVersion string
+	CreatedAt     time.Time
+	Findings      []BaselineEntry
+
+	fingerprints map[string]bool
+}
+
+// NewBaseline will create a baseline from a set of findings
+func NewBaseline(findings []*Finding, wraithVersion string) *Baseline {
+	b := &Baseline{
+		WraithVersion: wraithVersion,
+		CreatedAt:     time.Now(),
+		Findings:      []BaselineEntry{},
+	}
+	for _, f := range findings {
+		b.add(BaselineEntry{
+			Fingerprint:    f.Fingerprint,
+			SignatureID:    f.SignatureID,
+			Description:    f.Description,
+			FilePath:       f.FilePath,
+			LineNumber:     f.LineNumber,
+			RepositoryName: f.RepositoryName,
+			CommitHash:     f.CommitHash,
+		})
+	}
+	return b
+}
+
+// add will add an entry to the baseline if it is not already there
+func (b *Baseline) add(entry BaselineEntry) {
+	if b.fingerprints == nil {
+		b.fingerprints = make(map[string]bool)
+	}
+	if entry.Fingerprint == "" || b.fingerprints[entry.Fingerprint] {
+		return
+	}
+	b.fingerprints[entry.Fingerprint] = true
+	b.Findings = append(b.Findings, entry)
+}
+
+// Contains will check if a fingerprint is part of the baseline
+func (b *Baseline) Contains(fingerprint string) bool {
+	return b.fingerprints[fingerprint]
+}
+
+// Save will write the baseline to a file as json
+func (b *Baseline) Save(filename string) error {
+	data, err := json.MarshalIndent(b, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// ReadFindings will parse the json output of a previous scan into a list of findings. Findings exported
+// before fingerprints existed have them generated from the data that is available.
+func ReadFindings(data []byte) ([]*Finding, error) {
+	var findings []*Finding
+	if err := json.Unmarshal(data, &findings); err != nil {
+		return nil, err
+	}
+	for _, f := range findings {
+		if f.Fingerprint == "" && f.Content != "" {
+			f.SecretHash = HashSecret(f.Content)
+			f.Fingerprint = GenerateFingerprint(f.RepositoryOwner+"/"+f.RepositoryName, f.FilePath, f.SignatureID, f.SecretHash, f.CommitHash)
+		}
+	}
+	return findings, nil
+}
+
+// LoadBaseline will read in a baseline file. Both a baseline created by wraith and the json output of a
+// previous scan are accepted.
+func LoadBaseline(filename string) (*Baseline, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err == nil {
+		entries := b.Findings
+		b.Findings = []BaselineEntry{}
+		for _, e := range entries {
+			b.add(e)
+		}
+		return &b, nil
+	}
+
+	findings, err := ReadFindings(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a baseline nor a list of findings", filename)
+	}
+	return NewBaseline(findings, ""), nil
+}
@@ -0,0 +1,62 @@
+package core_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBaseline(t *testing.T) {
+
+	Convey("Given a set of findings", t, func() {
+		dir, err := ioutil.TempDir("", "wraith")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		findings := []*core.Finding{
+			{Fingerprint: "aaa", Content: "hunter2", FilePath: "config.yaml", SignatureID: "sig-1"},
+			{Fingerprint: "bbb", Content: "hunter3", FilePath: "config.yaml", SignatureID: "sig-1"},
+			{Fingerprint: "aaa", Content: "hunter2", FilePath: "config.yaml", SignatureID: "sig-1"},
+		}
+
+		Convey("When a baseline is created", func() {
+			b := core.NewBaseline(findings, "0.0.9")
+
+			Convey("Duplicate findings should only be added once", func() {
+				So(len(b.Findings), ShouldEqual, 2)
+				So(b.Contains("aaa"), ShouldBeTrue)
+				So(b.Contains("ccc"), ShouldBeFalse)
+			})
+
+			Convey("It should survive a round trip to disk without the secrets", func() {
+				f := filepath.Join(dir, "baseline.json")
+				So(b.Save(f), ShouldBeNil)
+
+				data, _ := ioutil.ReadFile(f)
+				So(string(data), ShouldNotContainSubstring, "hunter2")
+
+				loaded, err := core.LoadBaseline(f)
+				So(err, ShouldBeNil)
+				So(loaded.Contains("bbb"), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the baseline is the json output of a scan", func() {
+			f := filepath.Join(dir, "findings.json")
+			_ = ioutil.WriteFile(f, []byte(`[{"Fingerprint": "aaa"}, {"Content": "hunter2", "FilePath": "config.yaml"}]`), 0644)
+
+			loaded, err := core.LoadBaseline(f)
+
+			Convey("The fingerprints should be read or generated", func() {
+				So(err, ShouldBeNil)
+				So(loaded.Contains("aaa"), ShouldBeTrue)
+				So(len(loaded.Findings), ShouldEqual, 2)
+			})
+		})
+	})
+}
@@ -260,7 +260,7 @@ func staticImagesSpinnerGif() (*asset, error) {
 	return a, nil
 }
 
-var _staticIndexHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd4\x1a\x7f\x6f\xdb\xba\xf1\xff\x7e\x8a\x7b\x1c\xfc\xd0\x02\x95\x95\x2e\xc0\xc3\x90\x4a\xc6\xeb\x9a\x76\xcd\x43\xd3\x3e\x34\xd9\x86\xfd\x65\x50\xe2\x59\x62\x43\x91\x1a\x49\xc7\xc9\x86\x7d\xf7\x81\xa4\x24\x4b\xb2\x94\xb9\x6d\x56\xbc\x17\x04\xb6\x48\x1d\xef\x8e\xf7\x8b\x77\x47\x27\x3f\x30\x95\xdb\xfb\x1a\xa1\xb4\x95\x58\x3d\x49\xdc\x17\x08\x2a\x8b\x94\xa0\x24\x6e\x02\x29\x5b\x3d\x01\x00\x48\x2a\xb4\x14\xf2\x92\x6a\x83\x36\x25\x5b\xbb\x89\xfe\x44\xfa\xaf\x24\xad\x30\x25\xb7\x1c\x77\xb5\xd2\x96\x40\xae\xa4\x45\x69\x53\xb2\xe3\xcc\x96\x29\xc3\x5b\x9e\x63\xe4\x07\xcf\x81\x4b\x6e\x39\x15\x91\xc9\xa9\xc0\xf4\xc5\x73\x30\xa5\xe6\xf2\x26\xb2\x2a\xda\x70\x9b\x4a\x35\x81\x9a\xa1\xc9\x35\xaf\x2d\x57\xb2\x87\xfd\xef\x9a\x72\x5b\x9e\xc1\xaf\x5b\x6b\xb9\x2c\xc0\x96\x08\x1f\x6b\x94\x70\xa5\xb6\x3a\x47\xe0\x12\x3e\x5e\x5d\x7c\xb8\x9e\x40\x48\xb7\xb6\x54\xda\xf4\x90\x5d\xf2\xbc\xa4\x28\xe0\x1d\x4a\xcd\x6f\x0c\x4a\x78\xfa\x73\xc5\xf3\xb2\x1d\x3e\x7b\x0e\x97\xd4\xda\x7b\xf8\x45\x49\x34\xf0\xf4\xe7\x9c\x6e\x36\x1c\x25\xb5\xc8\xde\xc8\xe2\xd9\x73\xf8\x8b\xc6\x02\x7e\x51\xa5\x34\xca\x09\x30\xd0\xb4\xdc\x0a\x5c\x05\x4e\x93\x38\x8c\x9a\x57\x82\xcb\x1b\x28\x35\x6e\x52\x12\x1b\x7b\x2f\xd0\x94\x88\xd6\xc4\x99\x52\xd6\x58\x4d\xeb\x65\x6e\x0c\x01\x8d\x22\x25\xfb\xf7\xed\x66\xe6\x56\xab\x1a\x25\xcf\x95\xe4\xf9\x57\x2d\x2f\x79\x51\x0a\x5e\x94\xf6\xab\x56\xd3\xba\x16\x3c\xa7\x4e\x4f\x73\xeb\x93\x38\x18\xd6\x93\x24\x53\xec\xde\x7d\x4b\x7a\x0b\xb9\xa0\xc6\xa4\x44\xd2\xdb\x8c\x6a\x08\x5f\x11\xde\xd5\x54\xb2\xa8\x62\xed\x84\x67\x0c\xb2\x22\x3c\xb4\xcc\x30\xde\xad\x77\xda\xa4\x5c\xa2\x6e\xde\xf9\xf7\x74\x88\x3d\xca\x34\x95\x8c\xb4\xec\xf7\x20\x3d\x34\xaf\x0a\x30\x3a\x4f\x49\xcc\x2b\x5a\xa0\x89\x0b\x55\x97\xa8\xd7\x8e\xeb\x65\x2d\x0b\x02\xc1\xa8\xc9\xe9\x09\x81\x12\x1d\x23\x29\xf9\xe3\x09\x69\x89\xb0\x88\x4b\xc1\x25\x46\x99\x50\xf9\x0d\x01\x2a\x6c\x4a\x46\x44\x76\x8d\x39\xd0\x1e\x97\xd9\xd6\x5a\x25\x47\xac\x5a\x55\x14\x02\x35\x01\xe7\xa9\x29\x09\x30\x04\x18\xb5\xb4\x79\xe7\xf6\x2c\x04\xad\x0d\x12\xa0\x9a\xd3\x46\x68\xc8\x52\xb2\xa1\xc2\x20\x19\x10\x76\x7f\x1e\x4a\xd0\xcc\x69\xe6\xda\xe3\x70\xe2\xe5\x85\xd7\xda\x58\x1a\xa6\xa6\x33\x3c\x45\xce\xc8\xc8\x2a\x89\x1d\x48\x6f\x1f\x71\x60\xb2\xd1\x4d\xcc\xf8\xad\xd3\xb9\xa4\xb7\x4e\xd5\x15\xe5\x12\xb4\x72\x6c\xbb\x47\x32\xa7\xb7\x24\xd3\x71\x4f\xbb\x9c\x39\x23\xa2\xd6\xac\x27\x15\xdc\x33\x80\x5a\xab\x42\xa3\xb3\x3c\x6f\x74\x29\x09\x1a\x3a\x83\xd3\x93\xfa\xee\xe5\x78\x77\x13\x0b\x23\x67\x7f\xfd\x41\x64\xac\xe6\x35\xb2\xe1\x24\x95\xbc\x72\x9e\x4f\x9a\xdd\xb4\x2f\x33\xaa\x89\x67\xb7\x9d\x58\xbb\x99\x03\x1d\xb4\xdc\x79\x53\x3a\x83\x17\x27\x27\x8b\x97\x8d\xfe\x6e\xa9\xd8\xa2\x54\xbb\x94\xbc\x38\x39\xe9\xcf\x55\x5c\xa6\x64\x38\x43\xef\x02\xd4\xea\x22\xc4\x54\xfe\x2f\x2e\x8b\xe5\x72\x39\xdc\x65\xd0\xc1\xdc\xb0\x93\xf4\x58\x22\x5a\xed\x1e\x90\x57\xae\x44\x64\xaa\x11\xc0\x01\x10\xd5\x0c\x2c\xde\xd9\x28\x47\x69\xb1\x11\x8d\x9b\x5d\x6f\xb8\x64\x5c\x16\x66\x02\xc3\x14\x96\xc8\x05\x8b\x19\x58\x0f\x5f\x9e\x0e\xc0\x7d\xa0\x9d\x20\xb7\xf6\x82\x23\xab\x93\x24\x2e\x4f\x1f\x40\x57\x0f\xb1\xe1\x9d\x9d\x42\xe6\x8e\x25\xb2\xba\x56\x96\x0a\x78\xdb\x4c\x26\x71\x3d\xb3\xa5\xa1\xe0\x1f\x98\x9e\x9a\x7a\x54\xd1\x0b\xfc\x7e\x72\x17\xf8\x58\x42\x77\x98\x82\xc4\xdf\xba\x67\xb8\xca\xa9\x94\xc8\x7e\xfb\x02\xcf\x55\x55\x71\xfb\xbd\x44\xde\x50\x7b\x14\xa1\xb7\xb8\x82\xd8\x5f\x87\xd1\xef\x47\xf0\x1a\x6b\x65\xb8\x55\x9a\x7f\x37\x83\xef\x93\x7c\x14\x15\x0c\x10\x06\x3d\x7c\x72\x53\xbf\x1f\x2d\x58\xaa\x0b\xfc\x6e\xe6\xdf\x50\x7b\x14\xd9\xb7\xb8\x9a\x38\x1f\x46\xbf\x7d\x81\xb3\xad\x9e\x4a\xe6\xfe\x5f\x12\x6f\xc9\x75\x22\x3f\x39\xf3\xff\xdf\x22\xf9\x0e\x67\x10\xfd\x79\x33\x7c\x7c\xd9\xf7\x86\xcd\x63\x2f\x01\x0d\x8f\x06\x73\x47\x3b\xa4\x75\xb4\xc0\xa9\xdc\x25\x19\x6f\xb5\x4d\x07\x46\xc5\x85\xac\xb7\xb6\xdd\xf7\x46\xe9\x2a\x72\x09\xad\x56\x02\xfa\x83\xc8\x54\xb0\x11\x8a\xda\x48\xfb\x3a\xa7\xc9\xfe\x83\x88\x6a\x41\x73\x2c\x95\x60\xa8\x53\x72\x85\x54\xe7\xe5\x72\xb9\x9c\x48\x31\xc1\x33\xdc\xe5\x2a\xc6\x83\xf6\x59\xf6\xea\xd9\x0f\x2d\xcd\x04\xb6\xbc\x85\x81\xff\x74\xdc\x84\x87\x52\xdd\xa2\x6e\x27\x43\x56\x1c\x54\xe6\xa7\xe6\x52\xba\xc4\xee\x3b\x09\xfb\x39\x3d\xa1\x2c\x5b\x82\xc9\x55\x1d\x6a\x1a\xd2\xf7\x06\x9a\x07\x7b\x7e\x95\x07\x2b\xb0\xe5\x17\x2d\xaf\xa9\x2d\xc9\xea\x57\xea\x4b\xf0\x2f\x5b\x1a\x4e\xc0\xf6\xec\xfb\xe2\xe5\x5d\xf4\xbe\x6f\xc2\xb6\x7f\x3e\x44\x93\xc4\x63\x89\x38\x98\x09\xb9\x85\xa2\x79\x04\x38\x9c\x4c\x62\xaf\x8f\xd6\xa8\x1b\xf3\x75\x55\x98\xab\xb9\x56\xc9\x0f\x51\x04\xf1\xb2\xab\xa4\x20\x8a\x5c\x69\xb6\x51\xca\xa2\x7e\xa0\xa4\xee\x07\x9b\xf0\x5c\x6d\x5d\x05\x34\xa8\xb4\x43\x51\x5d\x5a\x5b\x9b\xb3\x38\x2e\xb8\x2d\xb7\xd9\x32\x57\x55\x5c\x51\x6b\xef\x3f\x2b\x89\x26\x0e\xd5\x2f\x81\x10\x5d\x53\xb2\xce\x04\x95\x37\x64\xb5\xaf\x8a\x81\x1b\xa0\xae\xe0\xfa\x8c\xb9\x85\xec\x1e\x12\x7a\x20\xf4\x23\x28\x1d\x92\xe8\x75\x70\x3c\x9d\x1f\x2b\xce\x98\xb2\x2f\xbf\x92\x40\xb3\x95\x98\x1b\xb3\x45\x13\x4b\xdc\x1d\x92\x74\x5a\xd7\x16\xa8\x04\x0f\xd5\x15\xfd\x5d\x69\xdc\x0a\xfe\x49\x12\x7a\x5c\x3d\x6f\x8f\x2d\x56\xb5\xa0\xb6\x89\xb6\xed\xa8\xf5\xb4\xb6\x58\xb6\x6c\xca\x57\xf6\x6a\x59\x00\xdf\xc0\xd3\xe0\x3b\x90\xa6\x40\x2e\x15\xe3\x9b\x7b\xf2\x0c\xfe\x0d\x8b\x1e\x5c\xbf\xd8\xcf\x28\x2b\x10\xfc\x67\x54\x6b\x5e\x51\x67\xc1\x97\x1f\xcf\x2f\xde\xfe\xe3\xa0\xe4\x5f\xc0\x7f\x00\x85\xc1\x31\x99\x0b\x69\x50\xdb\xa3\xc9\x98\x6d\x9e\xbb\xd2\x7d\xf5\xfa\xd3\x9b\x57\xd7\x6f\x8e\x26\x73\x8e\x02\x2d\x1e\x4d\x86\x51\x59\xa0\x26\xab\xf3\x37\xef\xdf\xcc\x50\x59\xb4\x2a\xb2\x6c\x52\xc4\x21\x9e\x24\xb9\x62\x38\xf2\xc5\xd6\x03\xfe\x40\x56\xc9\x22\x05\x5b\x72\xb3\x74\xa1\x9d\x5a\x8b\xcc\x95\x2d\x2e\x08\x3d\x7d\x06\x8b\xd5\xb0\xfb\x13\x7b\x5c\xb3\x04\xdb\x28\x14\x48\x76\x54\x92\x45\x04\x21\x30\xfd\xf5\xd3\x7b\x58\xac\x9a\x7e\x9b\x54\xaa\x46\xe7\xb1\x52\x69\xdc\xa0\xf6\x0d\xa4\x91\x59\x26\x8b\xf4\xc0\xe2\x3d\xb7\x95\x62\x28\x96\xa6\x54\xda\x06\xd4\xef\xa8\xd9\x73\xbc\x67\xb4\x9c\x64\xb4\x1f\xef\x06\x6c\xee\x83\xdf\x57\xb0\x1a\x0d\x58\xdd\xa3\xfa\xb8\x73\x4b\x17\xab\x78\x48\xe1\x03\xad\xb0\xe3\xd7\x31\x9a\xc4\xc1\xb5\xbe\xc6\xc9\xd6\x95\x62\x54\x4c\xf4\x1a\xfd\x7c\xe4\x62\xf4\xb0\x1b\x55\xfe\x34\x84\x08\xe9\x92\xaf\x59\xe1\x8a\x17\x92\xda\xad\x46\xb8\xa4\x36\x2f\xcf\xc0\x31\x7e\xbe\x6f\x6e\xc3\x62\x15\x52\x0f\x78\x1d\xfa\xd2\x93\x2b\x06\xd2\x68\x01\xdd\x7e\xcb\x9f\x0e\x1b\x8a\xc3\xce\x61\xab\x2a\xa1\x0c\x36\x7d\x44\xc6\x4d\xc5\x3b\x76\xc9\xa0\x3f\xf8\xda\xc3\x4d\xf5\x04\x3d\x54\xc9\x19\x43\x99\x12\xab\x5d\xde\xf7\xa3\xe5\x15\x9a\x97\x47\x75\x04\xa7\x85\x39\x4a\x44\x9b\xc0\xe5\xcd\x92\x9b\x6b\x34\xf6\x13\x3a\xd5\xb0\xa7\xcf\xc6\xce\xde\x43\x45\x05\xba\x78\xeb\x3e\xa3\x1d\xd5\xd2\x05\xca\xa6\x4b\xe7\x27\xc9\x2a\x31\x56\x2b\x59\xac\x3e\x28\xcb\x73\x3c\x4b\xe2\x66\x0c\xd7\x25\x37\xb0\x71\x8a\x12\x4a\xdd\x18\xb0\x0a\x32\x04\x8b\xc6\x5f\x2f\xe8\x40\x7c\x39\xdb\x4a\xeb\x85\x8e\x31\x53\x99\x95\x51\xa1\xd5\xb6\x86\xee\x69\x9c\xe5\x8d\xa4\x3c\xa9\xbe\x5e\x46\xb7\xbe\xe5\xb8\x5b\x6b\xba\x23\x3d\x1a\x1e\xbb\xc1\x5c\x49\xe6\x03\xf6\x27\xba\x1b\x8a\xff\x0b\xd1\x97\x78\xc7\xb6\x55\xfd\x10\x89\x77\x78\x07\x0e\xe6\x90\xce\x58\x3c\x83\x04\xb3\x21\x13\x55\x68\x69\xe4\xdf\x1c\xa4\x8d\xd3\x29\xa2\x4f\xe3\xce\xe6\x12\x31\xd6\x86\xc8\x46\xa5\xc3\xc0\xd0\xc6\x8b\x4e\xe3\xf1\x34\x5c\x17\x40\x3a\xb0\x45\x04\x6d\xf4\xf6\x2f\x0e\x43\x3f\x0c\x0e\x8c\xde\xcc\x38\xaf\x9b\xdb\xd7\x2b\x7f\x25\xf5\xd0\xce\xba\x68\x1f\x40\x3d\x23\xdf\x40\xf0\x12\x8d\xa1\x05\xce\x53\xdc\x17\x2a\xd2\x46\xdc\x52\xc1\xf3\xde\xb1\x66\xf5\x56\xe6\xce\x21\x02\x4f\x0d\xb6\xe6\xa4\xf8\x06\xb6\x2e\xce\x1f\x90\xc1\x74\xf1\x17\x54\xbe\x88\xe0\x0a\x73\x8d\xf6\xe2\xfc\x01\x1d\x41\xcf\xfa\xfb\xf6\xce\xd9\x3a\x17\xbc\xce\x14\xd5\xec\xc0\xde\xd5\xd6\xfa\x3b\x9d\xce\xee\x83\x17\x54\x93\x65\x57\xf7\xe7\xc3\x6b\x87\xd4\x97\xb8\xe1\x30\xec\xb1\xe9\xa2\x51\x2f\x51\x51\x1c\x14\xdf\xaf\xe9\xee\x57\xa6\xbd\x18\x8e\xb0\xb9\x41\x35\xe0\x27\xca\xb9\x8e\xff\x41\x37\xc1\x47\x65\xdf\x79\x5d\x9b\x9a\x4b\x89\x7a\xf2\x02\xc6\xe3\xe1\x55\xd1\xe2\x69\x60\xc9\xf0\x1e\xad\x99\x5d\x16\x7c\xd3\xdc\x8a\xbd\x57\xd4\x89\x3e\x44\xdc\xe6\x22\xd6\xb8\x62\x76\x86\x38\x89\x47\x34\xeb\xd5\x1c\x8a\x41\xa3\x60\x1c\x88\xda\x2b\xa5\x1e\x85\x76\xe9\xfc\xfe\x6a\x8d\x73\x8b\x9c\x96\x6a\x3d\x4e\x05\x27\x16\xb4\xe1\x74\x0c\x7f\xd8\x84\x18\xab\x26\x9c\x8f\xa1\x54\x20\x33\x49\xee\xbe\x26\x83\x9e\xc7\x86\xe7\x9d\xbf\x06\x6b\xef\x4d\x27\x4c\xce\xbf\xc9\xb6\x22\xeb\x4c\x0e\xae\x79\x7d\x06\x7f\xd6\x6a\x67\x10\xda\xda\xde\x15\x63\x5b\xd3\x5e\xb6\x7b\x3c\x93\x2e\x30\xc0\x4d\xb5\x56\xbb\x48\xe0\xc6\xee\x91\x53\xc9\x60\x82\x8d\x00\xda\x1c\x88\x1d\xac\x9b\x84\x1b\xbc\x37\xcb\x83\xe4\x82\x7a\x19\xbb\xc3\x2a\x72\x22\x26\xbd\x94\xd3\x05\xec\x07\x93\xcd\xa9\x6c\x73\xec\xf9\x6d\xed\xd3\xdf\x65\x48\x26\x9a\x43\x73\xf5\x37\x8e\xbb\x60\x7f\x4a\x86\x2d\xed\x63\x64\x81\xf6\x9d\x32\xd6\x9d\x27\x4d\x60\x6c\xbc\x99\x4e\x6f\xa1\xc9\xf3\xbf\x2c\xbd\x3f\x66\x1b\xfb\x03\xfb\x7f\x6c\x24\x70\x70\xd4\x56\xc6\x85\xec\x3e\xc7\x1e\x1b\xae\x63\x2f\xe3\x92\xe1\x5d\x4a\xa2\x17\x6d\x36\xc6\x38\x15\xaa\x18\x66\x1d\x0f\x27\xdb\x61\x05\x84\x81\xe8\xd2\x3a\xa6\xf2\x6d\x85\xd2\xce\xdc\x09\x07\xf0\xc6\x59\x9d\x55\x8d\x9d\xad\x6d\xfc\xb5\xb5\x41\x08\x5b\x9f\xe9\x2d\x0d\x13\x26\xfe\xfc\xcf\x2d\xea\xfb\xe8\x74\x79\xba\x7c\xb1\xfc\xec\x1d\xbe\xdd\xed\xfc\xa2\xad\x64\xa8\x4d\xae\x34\x1e\xbd\x24\xa3\xf9\x4d\xa6\xe4\xf1\x0b\x6a\x55\xd7\xa8\x8f\xc7\xdf\xfd\xb4\xe4\xd8\x15\xdd\x51\x74\xf4\x8a\x26\xc8\x1d\x0d\xdf\xff\xcd\xc8\x68\x4d\x1c\x1a\x5b\x49\x1c\x7e\xa3\xf4\xdf\x00\x00\x00\xff\xff\xc1\xea\xf6\x8f\xb4\x24\x00\x00")
+var _staticIndexHtml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xd5\x1a\x6b\x6f\xdc\xb8\xf1\x7b\x7e\x05\xc3\xc2\x81\x03\x44\x2b\xa7\x06\x0e\x85\xbd\xbb\x68\x2e\x4e\x2e\x2e\xf2\x38\xd8\x6e\x8b\xa2\x28\x16\x94\xc4\x95\x18\x4b\xa4\x4a\x52\x5e\xbb\xbd\xfb\xef\x9d\x21\x25\xad\xa4\x95\xf6\x36\x39\x37\xb8\x0b\xee\x6c\x91\x1c\xce\x0c\xe7\xc5\x99\xa1\xe7\x4f\x13\x15\xdb\x87\x92\x93\xcc\x16\xf9\xf2\xc9\x1c\x7f\x91\x9c\xc9\x74\x41\xb9\xa4\x38\xc1\x59\xb2\x7c\x42\xe0\xdf\xbc\xe0\x96\x91\x38\x63\xda\x70\xbb\xa0\x95\x5d\x07\x7f\xa2\xdd\x25\xc9\x0a\xbe\xa0\x77\x82\x6f\x4a\xa5\x2d\x25\xb1\x92\x96\x4b\x00\xdd\x88\xc4\x66\x8b\x84\xdf\x89\x98\x07\x6e\xf0\x82\x08\x29\xac\x60\x79\x60\x62\x96\xf3\xc5\xcb\x17\xc4\x64\x5a\xc8\xdb\xc0\xaa\x60\x2d\xec\x42\xaa\x11\xd4\x09\x37\xb1\x16\xa5\x15\x4a\x76\xb0\xff\x5d\x33\x61\xb3\x33\xf2\x63\x65\xad\x90\x29\xb1\x19\x27\x9f\x4a\x2e\xc9\xb5\xaa\x74\xcc\x81\x12\xf9\x74\x7d\xf9\xf1\x66\x04\x21\xab\x6c\xa6\xb4\xe9\x20\xfb\x20\xe0\x80\x3c\x27\xef\xb8\xd4\xe2\xd6\x00\x96\xe3\x3f\x17\x30\xd7\x0c\x9f\xbf\x20\x1f\x98\xb5\x0f\xe4\x2f\x4a\x72\x03\x8b\x31\x5b\xaf\x05\x97\xcc\xf2\xe4\x8d\x4c\x61\xf9\x07\xcd\x53\x58\xcd\xa4\x51\x28\x40\x4f\xd3\x0a\x9b\xf3\xa5\xe7\x74\x1e\xfa\x51\xbd\x94\xc3\xa9\x49\xa6\xf9\x7a\x41\x43\x63\x1f\x72\x6e\x32\xce\xad\x09\x23\xa5\xac\xb1\x9a\x95\xb3\xd8\x00\x87\x9a\xe7\x0b\xba\x5d\x6f\x0e\x33\xb5\x5b\x81\x00\x04\x9c\x4a\xc4\x5f\xb5\x3d\x13\x69\x96\xc3\xff\xf6\xab\x76\xb3\xb2\xcc\x45\xcc\x50\x4f\x53\xfb\xe7\xa1\x37\xac\x27\xf3\x48\x25\x0f\xf8\x5b\xb2\x3b\x12\xe7\xcc\x98\x05\x85\xcf\x88\x69\xe2\x7f\x05\xfc\xbe\x64\x32\x09\x8a\xa4\x99\x70\x8c\x91\x28\xf5\x1f\x0d\x33\x89\x68\xf7\xa3\x36\x99\x90\x5c\xd7\x6b\x6e\x9d\xf5\xb1\x07\x91\x06\xac\xb4\x61\xbf\x03\xe9\xa0\x45\x91\x12\xa3\x63\x58\x11\x05\x4b\xb9\x09\x53\x55\x66\x5c\xaf\x90\xeb\x59\x29\x53\x4a\xbc\x51\xd3\xd3\x13\xc0\xc1\x91\x91\x05\xfd\x23\x7c\xd7\x44\x92\x40\x48\x10\x0f\x0f\xa2\x5c\xc5\xb7\x94\xb0\x1c\xd6\x07\x44\x36\xb5\x39\xb0\x0e\x97\x11\x18\xb1\x92\x03\x56\xad\x4a\xd3\x1c\x4e\x43\xd0\x53\x17\xd4\xc3\x50\x92\x30\xcb\xea\x35\x3c\x73\x9e\xb3\xd2\x70\x20\xa5\x05\xab\x85\xc6\x93\x05\x5d\xb3\x1c\x66\x7b\x84\xf1\x9f\x83\xca\x59\x84\x9a\xb9\x71\x38\x50\xbc\x22\x75\x5a\x1b\x4a\xc3\x00\xb2\x71\x9e\x02\x34\x32\xba\x9c\x87\x08\xd2\x39\x47\xe8\x99\xac\x75\x13\x82\x72\x50\xe7\xb0\x17\x55\x5d\x80\x72\x88\x56\xc8\x36\x7e\xd2\x29\xbd\xcd\x23\x1d\x76\xb4\x2b\x12\x34\x22\x66\xcd\x6a\x54\xc1\x1d\x03\x28\xb5\x4a\x35\x47\xcb\x73\x46\xb7\xa0\x5e\x43\x67\xe4\xf4\xa4\xbc\x3f\x1f\x9e\x6e\x64\x63\x80\xf6\xd7\x1d\x04\xe0\x8a\xa2\xe4\x49\x7f\x92\x49\xb0\x0e\xf0\x7c\x5a\x9f\xa6\x59\x84\x35\xea\xd8\x6d\x26\x56\x38\xb3\xa3\x83\x86\x3b\x67\x4a\x67\xe4\xe5\xc9\xc9\xd1\x79\xad\xbf\x3b\x96\x57\x5c\xaa\xcd\x82\xc2\x6c\x77\xae\x10\x72\x41\xfb\x33\xec\xde\x43\x2d\x2f\x7d\x4c\x15\xff\x81\x30\x38\x9b\xcd\xfa\xa7\xf4\x3a\x98\x1a\xb6\x92\x1e\x4a\x44\xab\xcd\x1e\x79\x81\xd5\x05\xa6\x18\x00\xec\x00\x31\x9d\x10\xcb\xef\x6d\x10\x43\x8c\xe5\xb5\x68\x70\x76\xb5\x16\x32\x01\x66\xcd\x08\x86\x31\x2c\x01\x06\x8b\x09\x58\x07\x9f\x9d\xf6\xc0\x5d\xa0\x1d\x21\xb7\x72\x82\xa3\xcb\x13\x08\x43\xa7\x7b\xd0\x95\x7d\x6c\x70\x84\x31\x64\x78\x2d\xd1\xe5\x8d\xb2\x2c\x27\x6f\xeb\xc9\x79\x58\x4e\x1c\xa9\x2f\xf8\x3d\xd3\x63\x53\x8f\x2a\x7a\x88\xc6\xdf\x4c\xee\x40\xeb\x91\x84\x8e\x98\xbc\xc4\xdf\xe2\x37\xb9\x8e\x99\x94\x3c\xf9\xed\x0b\x3c\x56\x45\x21\xec\xb7\x12\x79\x4d\xed\x51\x84\xde\xe0\xf2\x62\x7f\xed\x47\xbf\x1f\xc1\x6b\x5e\x2a\x23\xac\xd2\xe2\x9b\x19\x7c\x97\xe4\xa3\xa8\xa0\x87\xd0\xeb\xe1\x0a\xa7\x7e\x3f\x5a\xb0\x4c\xa7\xfc\x9b\x99\x7f\x4d\xed\x51\x64\xdf\xe0\xaa\xe3\xbc\x1f\xfd\xf6\x05\x9e\x54\x7a\x2c\x99\xfb\x7f\x49\xbc\x21\xd7\x8a\xfc\xe4\xcc\xfd\xf7\x6b\x24\xdf\xe2\xf4\xa2\xbf\xa8\x87\x8f\x2f\xfb\xce\xb0\xfe\xec\x24\xa0\xfe\xd3\xf0\x18\x69\xfb\xb4\x0e\x4a\x82\xb1\xdc\x65\x3e\x3c\x6a\x93\x0e\x0c\x8a\x0b\x59\x56\xb6\x39\xf7\x5a\xe9\x22\xc0\x84\x16\x52\x48\xd2\x1d\x80\xf2\xc9\x3a\x57\xcc\x06\xda\xd5\x39\x75\xf6\xef\x45\x54\xe6\x2c\xe6\x99\xca\x13\xae\x17\xf4\x9a\x33\x1d\x67\x90\xf1\xd1\x31\xa9\x20\xc3\x6d\xae\x62\x1c\x68\x97\x65\xa7\x9e\xed\xd0\xb2\x08\x4a\x81\x9a\x37\x3f\x70\x3f\x91\x1b\xff\x91\xa9\x3b\xae\x9b\x49\x9f\x15\x7b\x95\xb9\xa9\xa9\x94\x6e\x6e\xb7\x9d\x84\xed\x9c\x1e\x51\x96\xcd\x88\x89\x55\xe9\x6b\x1a\xda\xf5\x06\x16\x7b\x7b\x7e\x15\x7b\x2b\xb0\xd9\x17\x6d\x2f\x99\x85\x93\xff\xc8\x5c\x09\xfe\x65\x5b\xfd\x0d\xd8\xdc\x7d\x5f\xbc\xbd\x8d\xde\x0f\x75\xd8\x76\xdf\xbb\x68\x60\x46\xef\xcc\x8c\xc9\xcd\x17\xcd\x03\xc0\xfe\x24\x4c\xa0\x3e\x1a\xa3\xae\xcd\x17\xab\x30\xac\xb9\x96\xf3\xa7\x41\x40\xc2\x59\x5b\x49\x91\x20\xc0\xd2\x6c\xad\x14\x44\x91\x3d\x25\x75\x37\xd8\xf8\xef\xa2\xc2\x0a\xa8\x57\x69\xfb\xa2\x3a\xb3\xb6\x34\x67\x61\x98\x42\x95\x5b\x45\x40\xaa\x00\xd2\xd6\x3e\x7c\xc6\xd6\x49\xe8\xab\x5f\xb0\x6a\x17\x4f\x17\x74\x15\xe5\x4c\xde\xd2\xe5\xb6\x2a\x26\xc2\x10\x86\x05\xd7\x67\xe0\x9d\x44\x0f\x80\x79\x47\xe8\x07\x50\xda\x25\xd1\xe9\xe0\x38\x3a\xcf\x0a\x91\x24\xca\x9e\x7f\x25\x81\xfa\x28\xa1\x30\xa6\x82\x91\xe4\x9b\x5d\x92\xa8\x75\x6d\x09\x94\xd1\x0e\xaa\x2d\xfa\xdb\xd2\xb8\x11\xfc\x93\xb9\xef\x71\x75\xbc\x3d\xb4\xbc\x00\x7f\xb7\x75\xb4\x6d\x46\x8d\xa7\x35\xc5\xb2\x4d\xc6\x7c\x65\xab\x96\x23\x22\xd6\xe4\xd8\xfb\x0e\x59\x2c\x08\xfd\xa0\x12\xb1\x7e\xa0\xcf\xc9\x7f\xc9\x51\x07\xae\x5b\xec\x47\x2c\x49\x39\x71\x3f\x83\x52\x43\xb1\x8b\x16\xfc\xe1\xd3\xc5\xe5\xdb\x7f\xec\x94\xfc\x47\xe4\x67\xc2\x73\xc3\x87\x64\x2e\xa5\xe1\xda\x1e\x4c\xc6\x54\x71\x8c\xa5\xfb\xf2\xf5\xd5\x9b\x57\x37\x6f\x0e\x26\x73\xc1\x73\x0e\x22\x3a\x94\x4c\xc2\x64\x8a\xfd\x83\x8b\x37\xef\xdf\x4c\x50\x39\x6a\x54\x64\x93\x51\x11\xfb\x78\x32\x8f\x55\xc2\x07\xbe\xd8\x78\xc0\x1f\x60\xf9\x68\x41\x6c\x26\xcc\x0c\x43\x3b\x98\x0c\x4f\xb0\x6c\xc1\x20\x74\xfc\x1c\x28\xf4\xbb\x3f\xa1\xc3\x35\x49\xb0\x89\x42\x9e\x64\x4b\x65\x7e\x14\x10\x1f\x98\xfe\x7a\xf5\x1e\x70\xd6\xfd\x36\xa9\xb0\x09\x08\x5e\x2a\x15\x80\x71\xed\x1a\x48\x03\xb3\x04\xee\x76\x2c\xde\x71\x5b\x00\x85\x7c\x66\x32\x30\x5a\x8f\xfa\x1d\x33\x5b\x8e\xb7\x8c\x66\xa3\x8c\x76\xe3\x5d\x8f\xcd\x6d\xf0\xfb\x0a\x56\x83\x1e\xab\x5b\x54\x9f\x36\xb8\xf5\x68\x19\xf6\x29\x7c\x64\x05\x6f\xf9\x45\x46\x41\xc9\xce\xb5\xbe\xc6\xc9\x56\x20\x10\x96\x8f\xf4\x1a\xdd\x7c\x80\x31\xba\xdf\x8d\xca\xbe\xeb\x43\xf8\x74\xc9\xd5\xac\xe4\x5a\xa4\x92\xd9\x4a\x73\xec\x25\xc7\xd9\x19\x41\xc6\x2f\xb6\xcd\x6d\xe0\xda\xa7\x1e\xa0\x56\xd7\x97\x1e\xdd\xd1\x93\x46\x03\x88\xe7\xcd\xbe\xdb\x6d\x28\xf6\x3b\x87\x8d\xaa\x72\x85\x0d\x43\xd7\x47\x4c\x84\x29\x44\xcb\x2e\xed\xf5\x07\x5f\x3b\xb8\xb1\x9e\xa0\x83\xca\x20\x7a\x72\x09\x32\xd3\x98\xf7\x3d\xb3\xa2\xe0\xe6\xfc\xa0\x8e\xe0\xb8\x30\x07\x89\x68\x1d\xb8\x9c\x59\x0a\x73\xc3\x8d\xbd\xe2\xa8\x9a\xe4\xf8\xf9\xd0\xd9\x3b\xa8\x58\xce\x31\xde\xe2\xcf\x60\xc3\xb4\xc4\x40\x59\x77\xe9\xdc\x24\x18\x14\x24\x2f\x4a\xa6\xcb\x8f\xca\x8a\x98\x9f\x01\xc3\x7e\x4c\x6e\x80\x12\xc1\x46\x03\xc9\x95\xba\x35\xc4\x2a\x12\x41\x16\x04\x84\xf1\x79\x41\x7b\xe2\xb3\xc9\x56\x5a\x27\x74\x0c\x99\x8a\xac\x0c\x52\xad\xaa\x92\xb4\x5f\xc3\x2c\x6f\x20\xe5\x51\xf5\x75\x32\xba\x15\x3e\xb7\xac\x34\xdb\xd0\x0e\x0d\x87\x1d\x2e\x7c\x25\x13\x17\xb0\xaf\xd8\xa6\x2f\xfe\x2f\x44\x9f\xf1\xfb\xa4\x2a\xca\x7d\x24\xde\xf1\x7b\x82\x30\xbb\x74\x86\xe2\xe9\x25\x98\x35\x99\x00\xdf\x64\x02\xb7\x42\x0f\x4b\x11\x5d\x1a\x77\x36\x95\x88\x25\x4d\x88\xac\x55\xda\x0f\x0c\x4d\xbc\x68\x35\x1e\x8e\xc3\xb5\x01\xa4\x05\x83\xe5\x26\x7a\xbb\x85\xdd\xd0\xdf\xbf\x30\xf6\xe4\x75\x53\xe7\x7a\xe5\x9e\xa4\xf6\x9d\xac\x8d\xf6\x1e\xd4\x31\xf2\x2b\x08\x7e\x80\xcb\x16\xca\x99\x69\x8a\xdb\x42\x45\xda\x40\x58\x96\x8b\xb8\x73\xad\x81\xd3\xcb\x18\x1d\xc2\xf3\x54\x63\xab\x6f\x8a\x03\xd8\xaa\x9d\x1b\x0c\x50\xad\xc9\x7b\xc8\x30\xc9\x53\xbc\xcd\x2b\x99\x70\x30\x0e\x2c\x2d\x9e\x3d\x73\xf3\x03\x5f\xdf\x7b\x26\x87\x07\x05\x85\x1f\x1f\xab\x22\x72\xfa\xde\x27\xd4\x52\xef\xd8\xa4\x7b\x13\xbc\xc7\x40\x71\x44\x56\x33\xce\xe2\xec\xf8\xb5\x9f\xfa\x9e\xc3\x65\xce\xc9\x4f\x3f\x91\x7f\xfe\xeb\x05\x59\x83\x00\x5c\xd4\x3e\xce\x5b\x36\x91\x36\x8e\x90\x63\x0c\x08\xcf\xcf\x71\xb2\x63\x65\xef\xfd\x62\x6b\x5b\x3b\x34\x5e\xad\x31\xc1\xde\x43\xe2\x49\x87\xc6\x96\x44\x08\x07\x39\x54\xf0\x3f\x1f\x2c\xcf\xcb\x8b\x3d\xb2\x1b\xaf\xc4\xbd\xff\x01\x8b\xd7\x3c\xd6\xdc\x5e\x5e\xec\x71\x98\x6e\x28\xea\x06\x1f\x91\xac\xe2\x5c\x94\x91\x62\x3a\xd9\x09\x3e\xaa\xb2\xee\x81\xad\x0d\x42\x3e\x24\x15\x74\xb2\xd7\x80\xff\xdc\x5d\xd7\x22\x75\xfd\x06\x9f\x99\x74\xd8\xc4\xab\xa1\x93\x35\x2a\x41\x94\xd8\xee\x69\x1f\xbb\xc6\x43\xea\x21\x01\xa0\x57\x9a\xf9\x6c\x61\xea\xf9\x65\xa7\xb5\xe3\xae\x48\xd7\x06\x5f\x99\x52\x48\x88\x64\xa3\xaf\x61\xed\x23\x66\x8d\xa7\x86\xa5\xfd\x47\xcd\x7a\x76\x96\x8a\x75\xfd\x44\xf9\x5e\x31\x14\xbd\xbf\xfe\xea\x57\x71\x83\x9d\x85\x09\xe2\x34\x1c\xd0\x2c\x97\x53\x28\x7a\x5d\x9b\xe1\xad\xd0\xbc\xef\x75\x28\x34\x5b\xa7\xcf\x87\x4e\x3b\xb1\x89\xd6\xae\xf0\x8b\x1b\x9a\xbb\x6d\x08\xbf\xdb\x11\x1a\x4f\x56\x7c\xdd\x46\x27\x2a\x8e\x6d\x81\x4c\x3a\xe1\xd3\x7f\x6f\xdc\x9b\x64\xf3\x88\x3d\x62\x72\x6e\x25\xaa\xf2\xa8\x35\x39\x72\x23\xca\x33\xf2\xbd\x56\x1b\xa8\x7f\x9a\x46\x0b\x56\xc6\x95\x69\xfe\xf2\xc1\xe1\x19\x75\x81\x1e\x6e\xa6\x01\x49\x90\xf3\xb5\xdd\x22\x67\x32\x21\x23\x6c\x78\xd0\x3a\x3b\x69\x61\x71\x92\xdc\xf2\x07\x33\xdb\xc9\xf4\x98\x93\x31\x66\x0e\x01\x8a\x98\x76\xf2\x7f\xbc\x3d\xf7\x66\xfe\x63\xa9\xff\xd0\xf3\x9b\x42\xb4\x7b\x4a\x9f\xd9\xd5\x19\xcc\xf2\x6f\x40\xdb\xdb\x1f\x44\x94\xb9\xf7\xd6\xe6\xc2\x02\xcc\xef\x94\xb1\x78\xb9\xd7\xb7\x54\xed\xcd\x6c\xfc\x08\x75\xd1\xf5\x65\xb5\xd6\x21\xc7\xd8\x66\x4f\xbf\x70\x10\xcf\xc1\x41\x47\x19\x76\x15\xb6\x05\xcf\xd0\x70\x91\xbd\x08\x2c\x88\xdf\x2f\x68\xf0\xb2\x49\x8d\x13\xc1\x72\x95\xf6\x53\xc0\xfd\x95\x8f\xdf\x41\xfc\x20\x6f\x73\xec\x44\xc5\x55\x01\x8e\x38\xf1\x40\xef\xc1\x6b\x67\x45\xab\x1a\x3a\x5b\xd3\x85\x6d\x0a\x35\x1f\xb6\x3e\xb3\x3b\xe6\x27\x4c\xf8\xf9\xdf\x15\xd7\x0f\xc1\xe9\xec\x74\xf6\x72\xf6\xd9\x39\x7c\x73\xda\xe9\x4d\x98\x56\x68\x13\x83\x92\x0e\xde\x12\xb1\xf8\x36\x52\xf2\xf0\x0d\xa5\x2a\x4b\x88\xa9\x07\xe3\x6f\xff\xce\xe7\xd0\x1d\xed\x55\x74\xf0\x8e\x3a\xc8\x1d\x0c\xdf\xfd\x03\x9e\xc1\x9e\xd0\x77\x19\xa1\xca\x74\x7f\x30\xf6\x3f\x68\xc9\x54\xa4\x41\x26\x00\x00")
 
 func staticIndexHtmlBytes() ([]byte, error) {
 	return bindataRead(
@@ -275,7 +275,7 @@ func staticIndexHtml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "static/index.html", size: 9396, mode: os.FileMode(436), modTime: time.Unix(1657482398, 0)}
+	info := bindataFileInfo{name: "static/index.html", size: 9793, mode: os.FileMode(436), modTime: time.Unix(1792280778, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -480,7 +480,7 @@ func staticJavascriptsUnderscoreJs() (*asset, error) {
 	return a, nil
 }
 
-var _staticStylesheetsApplicationCss = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x54\xed\x8a\xe3\x38\x10\xfc\x9f\xa7\xd0\x11\x0e\xee\xe0\x64\xec\x64\x72\xb9\xf3\xfc\x3c\xb8\x97\x58\x86\xd0\x96\xda\x76\x33\xb2\x24\xa4\xce\xc7\xec\x32\xef\xbe\xc8\x5f\x93\x64\x92\xcd\x2e\x21\x20\xe4\xae\xea\xee\x52\x57\x83\xf8\xb6\x10\x42\x08\xe5\x8c\x0b\xa5\x20\xdb\x62\x20\x7e\xee\xef\x18\x4f\x2c\x35\x2a\x17\x80\xc9\xd9\x52\xec\xad\xc6\x60\xc8\xe2\xf3\xe2\x7d\xb1\x80\xb2\x75\x07\x0c\x77\x08\x52\x40\x56\xb1\x1d\x3f\x7f\xe2\xb2\x6e\xa4\x51\x4e\xe3\x7d\x8e\xda\x39\x9e\x73\x54\x2e\x68\x0c\x92\x9d\x2f\x45\xe1\x4f\x22\x3a\x43\x5a\x2c\xd7\x79\xfa\x0d\x35\x77\x10\x1a\xb2\x43\xc8\x26\xf7\xa7\xe1\xd6\x83\xd6\x64\x9b\x52\xac\x72\x7f\x12\xe9\x5f\xe4\xe3\x69\x08\xa8\x9d\x65\x19\xe9\x2b\x96\xa2\x28\xd2\xe5\xfb\x62\x91\x59\x38\x54\x10\x04\x3c\x6c\x61\x8e\xbc\x50\xe4\x81\x7c\x99\x0f\xae\x09\x18\xa3\x4c\xd0\x33\x48\xa2\xa8\x8d\x3b\x96\x02\x8d\x21\x1f\x29\x0e\x35\x1e\x5b\x62\x94\xd1\x83\xc2\x94\xfb\x18\xc0\x0f\x1f\x3e\x00\x2d\x69\x8d\xb6\xa7\x5f\xd6\x64\x53\xcf\x71\x17\x11\x82\x6a\xc7\x0c\x47\xd2\xdc\x96\x62\xf5\x77\x3e\x76\xb9\x64\xa8\x0c\xee\xa6\x68\xc1\x3a\x53\xce\x48\x0f\xdc\x5e\xbe\xca\x52\x29\xf5\x10\x11\x39\x38\xdb\x5c\x01\xeb\xba\xbe\x09\xec\x61\xa0\x92\x38\x97\xd5\x6d\xee\x15\x77\x8e\xc8\x2a\xd0\x0d\x5e\x02\x8b\x3c\xff\xfd\x3e\x50\xb9\xae\x23\xbe\x44\x6c\xe7\x11\xe8\xb5\x07\x43\x8d\x2d\x45\xa0\xa6\xe5\xfb\x44\x01\xbd\x8b\xc4\x2e\xbc\x5d\xa9\x9a\xff\x22\x1b\x87\x8c\x31\xb2\x0c\x68\x80\x51\x8f\x6c\xce\x83\x22\x7e\x2b\x45\x9e\x3d\x0d\xa3\x12\x3d\x59\x3b\x0f\x96\xa6\xe8\x0d\xbc\x95\xa2\x32\x4e\xbd\x9e\xcf\x7d\x29\x56\x1b\x7f\x12\xb0\x67\x27\x8a\x7c\x3c\xf5\x14\x29\x53\xca\x2d\x23\x1a\x54\x1f\xb9\x2a\x50\xaf\x4d\x70\x7b\xab\xe5\xf4\x5c\xeb\xed\x06\xb6\xb5\xf8\x8d\x3a\xef\x02\x83\x1d\x4b\xef\x9c\x06\xb3\xab\xc9\xa0\xc8\xc0\x60\x60\x19\x51\x39\xab\x61\x56\xe1\x6c\x50\x1e\x22\x7e\x38\x28\xd9\x28\x90\xec\x90\x41\xf6\x75\x8f\x91\xe7\x3e\x5d\x4f\x3e\xbd\x11\x3d\x0f\xef\xe8\x7c\xd9\xbf\x41\xd9\x8b\x72\x61\x8f\x1d\xe9\x9d\x32\xe4\x2b\x07\x61\xd2\x84\x03\xd8\x58\xbb\xd0\x95\x22\x2a\x30\xf8\x47\x9e\x6d\xff\xbc\x16\x61\xa7\x9c\x65\xb4\x1c\xfb\x03\xd0\x8d\xe7\x99\xf7\xc3\x2d\xd8\x5f\xe2\xfc\xb6\xc5\x93\xde\x77\xfe\xa7\x18\x2e\x63\x3b\x38\xc9\x16\x87\xee\x9e\xf2\xb9\xbd\x1b\xf1\xcb\xb4\x7a\xa4\xdd\x77\xd5\xf5\xd2\x5e\xe6\x79\xa5\xfe\x51\x77\x91\xd1\x83\xfd\xa2\x81\x41\x46\x0e\x49\x4d\xd2\x2f\xb7\xeb\xff\x14\x69\xf7\xc6\xbc\x5c\x65\xfb\x7f\xfd\xef\x7f\xc5\x6a\x98\xdb\x03\x06\x26\x05\x66\xf2\x4a\x47\x5a\x1b\x1c\xb7\x76\x32\x59\xbf\x39\x7b\x7f\xd0\x61\xbc\x9f\xe5\x21\xdb\xb7\x74\x66\x83\xcf\xab\x30\xdd\x4e\xfa\x14\x9b\xc9\x9e\xd3\xbe\x78\xba\xe5\x57\x85\x96\x31\x24\x35\xbe\x07\x00\x00\xff\xff\x52\x19\xa4\xbe\x1e\x07\x00\x00")
+var _staticStylesheetsApplicationCss = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\x95\x54\x61\x8b\xdb\x30\x0c\xfd\xde\x5f\xe1\x51\x06\x1b\x2c\x21\xbd\x5e\xd7\x2d\xf7\x71\xb0\x3f\x31\x8e\xa2\xd8\x4e\x62\xce\xb1\x83\xad\x5e\x7b\x1b\xfb\xef\x93\x13\x27\x97\xf4\xd2\x75\x6b\x28\x18\x59\x4f\x92\x9f\xf4\x04\xec\xd7\x8a\xd1\x8f\x5b\x6d\x5d\xce\x94\xa9\xa5\x53\xf8\xd0\xd9\x50\x9e\x31\x11\x92\x5b\x07\xa8\xac\xc9\xd9\xd1\x08\xe9\xb4\x32\xf2\x61\xf5\x7b\xb5\x82\xbc\xb6\xcf\xd2\x5d\x09\x10\x1c\xd2\x02\x4d\xbc\x7e\x13\xcb\xd8\x18\x86\x5b\x21\xaf\xc7\x28\xad\xc5\x31\x47\x61\x1d\x15\x90\xa0\x6d\x73\xb6\x69\xcf\xcc\x5b\xad\x04\x5b\x6f\xb3\xf0\xf5\x35\x37\xe0\x2a\x65\x7a\x97\x5d\xd6\x9e\x7b\x6b\x0b\x42\x28\x53\xe5\xec\x8e\x4c\x2c\xfc\x37\x59\x3c\xf5\x0e\xa5\x35\x98\x78\xf5\x53\x52\xe0\x4d\x30\x52\xea\xd4\xc0\x73\x01\x8e\xc1\xcd\x27\x8c\x9e\x33\x46\x6e\xd0\x97\xb6\xce\x56\x4e\x7a\x9f\x04\xe8\x04\x12\x42\x94\xda\x9e\x72\x26\xb5\x56\xad\x57\xbe\xaf\xf1\x54\x2b\x94\x89\x6f\x81\xcb\x90\xfb\xe4\xa0\xed\x2f\x5e\x01\xb5\x12\x42\x9a\x2e\xfc\xba\x54\x26\xbc\xd9\x1f\xbc\x04\xc7\xeb\x98\xe1\xa4\x04\xd6\xc4\xc3\xe7\x2c\xbe\x72\x8d\x50\x68\x79\x18\xbc\x19\x8a\x94\xda\x90\xb4\x80\xf5\xbc\x2b\x6b\xce\xf9\x4d\x84\x47\x67\x4d\x75\x01\x2c\xcb\x72\x11\xd8\xc1\x80\x07\x72\xe6\xd5\xed\xae\x15\x37\x45\xa4\x05\x88\x4a\xce\x81\xd4\xd5\xf7\xd7\x81\xdc\x36\x8d\xc2\x39\x62\x3f\x8e\x40\xc7\x3d\x68\x55\x51\xa7\x9c\xaa\x6a\xbc\x1e\xc8\xc9\xd6\x7a\x85\xd6\xbd\x5c\xb0\x9a\xfd\x67\x34\x74\x29\x4a\x8f\x14\x50\x03\x4a\x11\xa3\x59\x6a\xb1\xc2\x97\x9c\x65\xe9\x7d\x3f\x2a\xbe\x55\xc6\x8c\x83\x25\x94\x6f\x35\xd0\x7d\xa1\x2d\x7f\x9a\xce\x3d\x95\xb0\xa3\xb1\x86\x23\x5a\xe2\x22\x9e\xba\x10\x21\x53\xc8\x9d\x78\xa9\x25\x7f\xcd\x55\x00\x7f\xaa\x9c\xa5\xd1\x4c\x86\x76\x6d\xf7\x3b\xd8\x97\xec\x9d\x6a\x5a\xeb\x10\x4c\x2c\xbd\xb1\x02\x34\x95\xae\x25\x4b\x41\x4b\x47\x7a\xa1\xd9\x36\x02\x46\x16\x26\x83\x72\x13\xf1\xd7\x41\x49\x23\x41\x49\x23\x11\x92\xae\xee\xe8\x39\xd5\xe9\x76\xd0\xe9\x82\xf7\x38\xbc\x51\xf9\x49\xd7\x83\xbc\x23\x65\x0e\xa2\x82\x42\xaf\xa2\x7b\x5c\x1f\x85\x45\xb4\x0d\x35\x60\x26\xa5\x83\x12\x07\x4e\x8a\x2c\x2c\xb8\x81\x3f\x74\x60\x7c\x69\x1d\x39\x7b\x4e\x8f\xfc\x90\xa5\xfb\x8f\x97\x84\x1d\xba\x24\x06\x7d\x77\x00\xb5\xd0\xca\x71\x97\x2c\xc1\x3e\xb1\xa9\xb5\x96\x67\x71\x6c\xda\x7f\x8a\x30\xf7\x6d\xe0\x9c\xd4\xb2\x67\xe2\x3e\x1b\x45\xb6\xe0\xbf\x0e\x6b\x2a\x31\xc7\xa6\xb8\x5c\xf0\xeb\x2c\x2b\xf8\x17\x7e\x15\x49\xfb\xc9\xfc\x10\x40\x9d\xa0\x16\x07\x82\x95\x78\x5c\xae\xff\x8d\xa7\x39\x6a\xfd\x78\x91\xed\xfb\xf6\xeb\xb7\xcd\x5d\x3f\xe3\xb4\xe6\x50\x11\xc9\x83\xae\x1a\xda\x76\x5a\xc6\x0d\x1f\x04\xd9\x6d\xd9\x4e\x4b\xea\x39\xda\x47\x7a\x94\xe9\x9e\x34\x91\xcc\xdb\xb5\x19\xac\x03\x3f\x9b\xdd\x20\xe5\x61\xb7\xdc\x2f\x69\x9b\x53\x7f\xa4\x0b\x6c\xfc\x01\x5f\x39\xa5\x25\x4a\x07\x00\x00")
 
 func staticStylesheetsApplicationCssBytes() ([]byte, error) {
 	return bindataRead(
@@ -495,7 +495,7 @@ func staticStylesheetsApplicationCss() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "static/stylesheets/application.css", size: 1822, mode: os.FileMode(436), modTime: time.Unix(1655955640, 0)}
+	info := bindataFileInfo{name: "static/stylesheets/application.css", size: 1866, mode: os.FileMode(436), modTime: time.Unix(1792280778, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -0,0 +1,463 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/transport"
+	githttp "gopkg.in/src-d/go-git.v4/plumbing/transport/http"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+// These are the base urls for Bitbucket Cloud, Bitbucket Server urls are given on the command line
+const (
+	BitbucketAPIURL = "https://api.bitbucket.org/2.0"
+	BitbucketURL    = "https://bitbucket.org"
+)
+
+// bitbucketClient holds a Bitbucket Cloud or Bitbucket Server api client. The two use different apis so
+// server is used to decide which one is called.
+type bitbucketClient struct {
+	apiURL     string
+	httpClient *http.Client
+	logger     *Logger
+	server     bool
+	token      string
+	username   string
+}
+
+// bitbucketLink is a link to a resource in both Bitbucket Cloud and Bitbucket Server
+type bitbucketLink struct {
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
+// bitbucketCloudPage is a page of results from the Bitbucket Cloud api
+type bitbucketCloudPage struct {
+	Next   string          `json:"next"`
+	Values json.RawMessage `json:"values"`
+}
+
+// bitbucketServerPage is a page of results from the Bitbucket Server api
+type bitbucketServerPage struct {
+	IsLastPage    bool            `json:"isLastPage"`
+	NextPageStart int             `json:"nextPageStart"`
+	Values        json.RawMessage `json:"values"`
+}
+
+// bitbucketCloudUser is a user or workspace in Bitbucket Cloud
+type bitbucketCloudUser struct {
+	UUID        string `json:"uuid"`
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Nickname    string `json:"nickname"`
+	Links       struct {
+		Avatar bitbucketLink `json:"avatar"`
+		HTML   bitbucketLink `json:"html"`
+	} `json:"links"`
+}
+
+// bitbucketCloudRepository is a repository in Bitbucket Cloud
+type bitbucketCloudRepository struct {
+	UUID        string          `json:"uuid"`
+	Slug        string          `json:"slug"`
+	FullName    string          `json:"full_name"`
+	Description string          `json:"description"`
+	Website     string          `json:"website"`
+	Parent      json.RawMessage `json:"parent"`
+	MainBranch  struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+	Links struct {
+		Clone []bitbucketLink `json:"clone"`
+		HTML  bitbucketLink   `json:"html"`
+	} `json:"links"`
+}
+
+// bitbucketServerUser is a user in Bitbucket Server
+type bitbucketServerUser struct {
+	ID           int64  `json:"id"`
+	Slug         string `json:"slug"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// bitbucketServerProject is a project in Bitbucket Server
+type bitbucketServerProject struct {
+	ID          int64  `json:"id"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// bitbucketServerRepository is a repository in Bitbucket Server
+type bitbucketServerRepository struct {
+	ID      int64                  `json:"id"`
+	Slug    string                 `json:"slug"`
+	Name    string                 `json:"name"`
+	Project bitbucketServerProject `json:"project"`
+	Origin  json.RawMessage        `json:"origin"`
+	Links   struct {
+		Clone []bitbucketLink `json:"clone"`
+		Self  []bitbucketLink `json:"self"`
+	} `json:"links"`
+}
+
+// NewClient creates a Bitbucket api client. If no username is given the token is sent as a bearer token,
+// otherwise the username and token are used for basic auth, which is what app passwords need.
+func (c bitbucketClient) NewClient(apiURL string, username string, token string, server bool, logger *Logger) bitbucketClient {
+	c.apiURL = strings.TrimSuffix(apiURL, "/")
+	c.httpClient = &http.Client{Timeout: time.Minute}
+	c.logger = logger
+	c.server = server
+	c.token = token
+	c.username = username
+	return c
+}
+
+// cloneBitbucket will clone a Bitbucket repo to memory or a temp dir. If there is no username the token is sent
+// as a bearer token, which is how Bitbucket Server HTTP access tokens are used on their own.
+func cloneBitbucket(cloneConfig *CloneConfiguration) (*git.Repository, string, error) {
+
+	// Public repos can be cloned without any credentials
+	var auth transport.AuthMethod
+	if *cloneConfig.Username != "" {
+		auth = &githttp.BasicAuth{
+			Username: *cloneConfig.Username,
+			Password: *cloneConfig.Token,
+		}
+	} else if *cloneConfig.Token != "" {
+		auth = &githttp.TokenAuth{Token: *cloneConfig.Token}
+	}
+
+	cloneOptions := &git.CloneOptions{
+		URL:           *cloneConfig.URL,
+		Depth:         *cloneConfig.Depth,
+		ReferenceName: plumbing.ReferenceName(fmt.Sprintf("refs/heads/%s", *cloneConfig.Branch)),
+		SingleBranch:  true,
+		Tags:          git.NoTags,
+		Auth:          auth,
+	}
+
+	// An empty repo does not have a main branch so we clone whatever HEAD points to
+	if *cloneConfig.Branch == "" {
+		cloneOptions.ReferenceName = ""
+	}
+
+	// Every branch and tag is fetched when more than the default branch is being scanned
+	if *cloneConfig.AllRefs {
+		cloneOptions.SingleBranch = false
+		cloneOptions.Tags = git.AllTags
+	}
+
+	var repository *git.Repository
+	var err error
+	var dir string
+	if !*cloneConfig.InMemClone {
+		dir, err = ioutil.TempDir("", "wraith")
+		if err != nil {
+			return nil, "", err
+		}
+		repository, err = git.PlainClone(dir, false, cloneOptions)
+	} else {
+		repository, err = git.Clone(memory.NewStorage(), nil, cloneOptions)
+	}
+	if err != nil {
+		return nil, dir, err
+	}
+	return repository, dir, nil
+}
+
+// httpsCloneURL will find the https clone url in a set of links and strip any username from it, the
+// credentials are given when cloning.
+func httpsCloneURL(links []bitbucketLink) string {
+	for _, link := range links {
+		if link.Name != "https" && link.Name != "http" {
+			continue
+		}
+		u, err := url.Parse(link.Href)
+		if err != nil {
+			return link.Href
+		}
+		u.User = nil
+		return u.String()
+	}
+	return ""
+}
+
+// getJSON will make an authenticated request to the api and decode the response into v
+func (c bitbucketClient) getJSON(endpoint string, v interface{}) error {
+	if !strings.HasPrefix(endpoint, "http") {
+		endpoint = c.apiURL + endpoint
+	}
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", UserAgent)
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.token)
+	} else if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// getAllCloud will get every page of a Bitbucket Cloud api call and hand each page of values to add
+func (c bitbucketClient) getAllCloud(endpoint string, add func(json.RawMessage) error) error {
+	for endpoint != "" {
+		var page bitbucketCloudPage
+		if err := c.getJSON(endpoint, &page); err != nil {
+			return err
+		}
+		if err := add(page.Values); err != nil {
+			return err
+		}
+		endpoint = page.Next
+	}
+	return nil
+}
+
+// getAllServer will get every page of a Bitbucket Server api call and hand each page of values to add
+func (c bitbucketClient) getAllServer(endpoint string, add func(json.RawMessage) error) error {
+	start := 0
+	for {
+		var page bitbucketServerPage
+		if err := c.getJSON(fmt.Sprintf("%s?limit=100&start=%d", endpoint, start), &page); err != nil {
+			return err
+		}
+		if err := add(page.Values); err != nil {
+			return err
+		}
+		if page.IsLastPage {
+			return nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+// GetUserOrganization is used to enumerate a Bitbucket Cloud workspace, or a Bitbucket Server project or user
+func (c bitbucketClient) GetUserOrganization(login string) (*Owner, error) {
+	emptyString := ""
+	if !c.server {
+		var workspace bitbucketCloudUser
+		if err := c.getJSON("/workspaces/"+url.PathEscape(login), &workspace); err != nil {
+			return nil, err
+		}
+		return &Owner{
+			Login:     &workspace.Slug,
+			ID:        hashID(workspace.UUID),
+			Type:      stringPtr(TargetTypeOrganization),
+			Name:      &workspace.Name,
+			AvatarURL: &workspace.Links.Avatar.Href,
+			URL:       &workspace.Links.HTML.Href,
+			Company:   &emptyString,
+			Blog:      &emptyString,
+			Location:  &emptyString,
+			Email:     &emptyString,
+			Bio:       &emptyString,
+		}, nil
+	}
+
+	// Personal repos on Bitbucket Server live in a project named after the user, so a user is tried if
+	// there is no project with the given key
+	var project bitbucketServerProject
+	projectErr := c.getJSON("/projects/"+url.PathEscape(login), &project)
+	if projectErr != nil {
+		var user bitbucketServerUser
+		if err := c.getJSON("/users/"+url.PathEscape(login), &user); err != nil {
+			return nil, fmt.Errorf("no Bitbucket project or user %s was found: %s", login, projectErr)
+		}
+		return c.serverUserOwner(user), nil
+	}
+	return &Owner{
+		Login:     &project.Key,
+		ID:        hashID("project:" + project.Key),
+		Type:      stringPtr(TargetTypeOrganization),
+		Name:      &project.Name,
+		AvatarURL: &emptyString,
+		URL:       stringPtr(fmt.Sprintf("%s/projects/%s", c.serverURL(), project.Key)),
+		Company:   &emptyString,
+		Blog:      &emptyString,
+		Location:  &emptyString,
+		Email:     &emptyString,
+		Bio:       &project.Description,
+	}, nil
+}
+
+// serverUserOwner will create an owner for a Bitbucket Server user
+func (c bitbucketClient) serverUserOwner(user bitbucketServerUser) *Owner {
+	emptyString := ""
+	return &Owner{
+		Login:     &user.Slug,
+		ID:        hashID("user:" + user.Slug),
+		Type:      stringPtr(TargetTypeUser),
+		Name:      &user.DisplayName,
+		AvatarURL: &emptyString,
+		URL:       stringPtr(fmt.Sprintf("%s/users/%s", c.serverURL(), user.Slug)),
+		Company:   &emptyString,
+		Blog:      &emptyString,
+		Location:  &emptyString,
+		Email:     &user.EmailAddress,
+		Bio:       &emptyString,
+	}
+}
+
+// serverURL will get the web url of Bitbucket Server from the api url
+func (c bitbucketClient) serverURL() string {
+	return strings.TrimSuffix(c.apiURL, "/rest/api/1.0")
+}
+
+// GetOrganizationMembers will gather the members of a Bitbucket Cloud workspace or the users with access
+// to a Bitbucket Server project
+func (c bitbucketClient) GetOrganizationMembers(target Owner) ([]*Owner, error) {
+	var allMembers []*Owner
+
+	if !c.server {
+		err := c.getAllCloud("/workspaces/"+url.PathEscape(*target.Login)+"/members?pagelen=100", func(values json.RawMessage) error {
+			var members []struct {
+				User bitbucketCloudUser `json:"user"`
+			}
+			if err := json.Unmarshal(values, &members); err != nil {
+				return err
+			}
+			for _, m := range members {
+				// Every user has a personal workspace that can be found with their UUID
+				user := m.User
+				allMembers = append(allMembers, &Owner{
+					Login: &user.UUID,
+					ID:    hashID(user.UUID),
+					Type:  stringPtr(TargetTypeUser),
+					Name:  &user.DisplayName,
+				})
+			}
+			return nil
+		})
+		return allMembers, err
+	}
+
+	err := c.getAllServer("/projects/"+url.PathEscape(*target.Login)+"/permissions/users", func(values json.RawMessage) error {
+		var members []struct {
+			User bitbucketServerUser `json:"user"`
+		}
+		if err := json.Unmarshal(values, &members); err != nil {
+			return err
+		}
+		for _, m := range members {
+			allMembers = append(allMembers, c.serverUserOwner(m.User))
+		}
+		return nil
+	})
+	return allMembers, err
+}
+
+// GetRepositoriesFromOwner is used gather all the repos in a workspace, project or the personal repos of a user.
+// Forks are not captured, the same as gitlab.
+func (c bitbucketClient) GetRepositoriesFromOwner(target Owner) ([]*Repository, error) {
+	var allRepos []*Repository
+
+	if !c.server {
+		err := c.getAllCloud("/repositories/"+url.PathEscape(*target.Login)+"?pagelen=100", func(values json.RawMessage) error {
+			var repos []bitbucketCloudRepository
+			if err := json.Unmarshal(values, &repos); err != nil {
+				return err
+			}
+			for _, repo := range repos {
+				if len(repo.Parent) > 0 && string(repo.Parent) != "null" {
+					continue
+				}
+				repo := repo
+				owner := strings.SplitN(repo.FullName, "/", 2)[0]
+				allRepos = append(allRepos, &Repository{
+					Owner:         &owner,
+					ID:            hashID(repo.UUID),
+					Name:          &repo.Slug,
+					FullName:      &repo.FullName,
+					CloneURL:      stringPtr(httpsCloneURL(repo.Links.Clone)),
+					URL:           &repo.Links.HTML.Href,
+					DefaultBranch: &repo.MainBranch.Name,
+					Description:   &repo.Description,
+					Homepage:      &repo.Website,
+				})
+			}
+			return nil
+		})
+		return allRepos, err
+	}
+
+	key := *target.Login
+	if *target.Type == TargetTypeUser {
+		key = "~" + key
+	}
+	err := c.getAllServer("/projects/"+url.PathEscape(key)+"/repos", func(values json.RawMessage) error {
+		var repos []bitbucketServerRepository
+		if err := json.Unmarshal(values, &repos); err != nil {
+			return err
+		}
+		for _, repo := range repos {
+			if len(repo.Origin) > 0 && string(repo.Origin) != "null" {
+				continue
+			}
+			repo := repo
+			id := repo.ID
+			webURL := ""
+			if len(repo.Links.Self) > 0 {
+				webURL = strings.TrimSuffix(repo.Links.Self[0].Href, "/browse")
+			}
+			allRepos = append(allRepos, &Repository{
+				Owner:         &repo.Project.Key,
+				ID:            &id,
+				Name:          &repo.Slug,
+				FullName:      stringPtr(repo.Project.Key + "/" + repo.Slug),
+				CloneURL:      stringPtr(httpsCloneURL(repo.Links.Clone)),
+				URL:           &webURL,
+				DefaultBranch: stringPtr(c.getServerDefaultBranch(repo.Project.Key, repo.Slug)),
+				Description:   &repo.Project.Description,
+				Homepage:      &webURL,
+			})
+		}
+		return nil
+	})
+	return allRepos, err
+}
+
+// getServerDefaultBranch will get the default branch of a Bitbucket Server repo. Bitbucket Server does not
+// return this with the repo and the endpoint has moved between versions, so both are tried.
+func (c bitbucketClient) getServerDefaultBranch(key string, slug string) string {
+	var branch struct {
+		DisplayID string `json:"displayId"`
+	}
+	repoPath := fmt.Sprintf("/projects/%s/repos/%s", url.PathEscape(key), url.PathEscape(slug))
+	for _, endpoint := range []string{repoPath + "/default-branch", repoPath + "/branches/default"} {
+		if err := c.getJSON(endpoint, &branch); err == nil && branch.DisplayID != "" {
+			return branch.DisplayID
+		}
+	}
+	c.logger.Debug("Unable to get the default branch of %s/%s, using master\n", key, slug)
+	return "master"
+}
+
+// stringPtr will return a pointer to a copy of a string
+func stringPtr(s string) *string {
+	return &s
+}
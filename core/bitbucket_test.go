@@ -0,0 +1,141 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBitbucketCloudClient(t *testing.T) {
+
+	Convey("Given a Bitbucket Cloud api that returns its results in pages", t, func() {
+		var server *httptest.Server
+		var auth []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/workspaces/ws/members", func(w http.ResponseWriter, r *http.Request) {
+			auth = append(auth, r.Header.Get("Authorization"))
+			fmt.Fprintf(w, `{"next": "%s/members/2", "values": [{"user": {"uuid": "{1}", "display_name": "One"}}]}`, server.URL)
+		})
+		mux.HandleFunc("/members/2", func(w http.ResponseWriter, r *http.Request) {
+			auth = append(auth, r.Header.Get("Authorization"))
+			fmt.Fprint(w, `{"values": [{"user": {"uuid": "{2}", "display_name": "Two"}}]}`)
+		})
+		mux.HandleFunc("/repositories/ws", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"values": [
+				{"uuid": "{r1}", "slug": "app", "full_name": "ws/app", "mainbranch": {"name": "develop"},
+				 "links": {"clone": [{"name": "ssh", "href": "git@bitbucket.org:ws/app.git"},
+				                     {"name": "https", "href": "https://user@bitbucket.org/ws/app.git"}]}},
+				{"uuid": "{r2}", "slug": "fork", "full_name": "ws/fork", "parent": {"full_name": "other/fork"}}
+			]}`)
+		})
+		server = httptest.NewServer(mux)
+		defer server.Close()
+
+		c := bitbucketClient.NewClient(bitbucketClient{}, server.URL+"/", "user", "app-password", false, &Logger{})
+
+		Convey("When the members of a workspace are gathered", func() {
+			members, err := c.GetOrganizationMembers(Owner{Login: stringPtr("ws")})
+
+			Convey("Every page should be followed with the same credentials", func() {
+				So(err, ShouldBeNil)
+				So(members, ShouldHaveLength, 2)
+				So(*members[0].Login, ShouldEqual, "{1}")
+				So(*members[1].Login, ShouldEqual, "{2}")
+				So(auth, ShouldHaveLength, 2)
+				So(auth[1], ShouldEqual, auth[0])
+				So(auth[0], ShouldStartWith, "Basic ")
+			})
+		})
+
+		Convey("When the repos of a workspace are gathered", func() {
+			repos, err := c.GetRepositoriesFromOwner(Owner{Login: stringPtr("ws")})
+
+			Convey("Forks should be skipped and the main branch should be the default branch", func() {
+				So(err, ShouldBeNil)
+				So(repos, ShouldHaveLength, 1)
+				So(*repos[0].DefaultBranch, ShouldEqual, "develop")
+				So(*repos[0].CloneURL, ShouldEqual, "https://bitbucket.org/ws/app.git")
+			})
+		})
+
+		Convey("When a page can not be found", func() {
+			_, err := c.GetRepositoriesFromOwner(Owner{Login: stringPtr("missing")})
+
+			Convey("An error should be returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestBitbucketServerClient(t *testing.T) {
+
+	Convey("Given a Bitbucket Server api that returns its results in pages", t, func() {
+		var auth string
+		mux := http.NewServeMux()
+		mux.HandleFunc("/rest/api/1.0/projects/PRJ/repos", func(w http.ResponseWriter, r *http.Request) {
+			auth = r.Header.Get("Authorization")
+			switch r.URL.Query().Get("start") {
+			case "0":
+				fmt.Fprint(w, `{"isLastPage": false, "nextPageStart": 2, "values": [
+					{"id": 1, "slug": "new", "project": {"key": "PRJ"}},
+					{"id": 2, "slug": "old", "project": {"key": "PRJ"}}
+				]}`)
+			case "2":
+				fmt.Fprint(w, `{"isLastPage": true, "values": [
+					{"id": 3, "slug": "none", "project": {"key": "PRJ"}},
+					{"id": 4, "slug": "fork", "project": {"key": "PRJ"}, "origin": {"slug": "other"}}
+				]}`)
+			default:
+				http.NotFound(w, r)
+			}
+		})
+		mux.HandleFunc("/rest/api/1.0/projects/PRJ/repos/new/default-branch", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"id": "refs/heads/main", "displayId": "main"}`)
+		})
+		mux.HandleFunc("/rest/api/1.0/projects/PRJ/repos/old/branches/default", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"id": "refs/heads/develop", "displayId": "develop"}`)
+		})
+		mux.HandleFunc("/rest/api/1.0/projects/~JDOE/repos", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{"id": 5, "slug": "personal", "project": {"key": "~JDOE"}}]}`)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		c := bitbucketClient.NewClient(bitbucketClient{}, server.URL+"/rest/api/1.0", "", "token", true, &Logger{})
+
+		Convey("When the repos of a project are gathered", func() {
+			repos, err := c.GetRepositoriesFromOwner(Owner{Login: stringPtr("PRJ"), Type: stringPtr(TargetTypeOrganization)})
+
+			Convey("Every page should be followed and forks should be skipped", func() {
+				So(err, ShouldBeNil)
+				So(repos, ShouldHaveLength, 3)
+				So(*repos[0].Name, ShouldEqual, "new")
+				So(*repos[2].Name, ShouldEqual, "none")
+				So(auth, ShouldEqual, "Bearer token")
+			})
+
+			Convey("The default branch should be found with either endpoint", func() {
+				So(*repos[0].DefaultBranch, ShouldEqual, "main")
+				So(*repos[1].DefaultBranch, ShouldEqual, "develop")
+			})
+
+			Convey("The default branch should be master when neither endpoint has it", func() {
+				So(*repos[2].DefaultBranch, ShouldEqual, "master")
+			})
+		})
+
+		Convey("When the repos of a user are gathered", func() {
+			repos, err := c.GetRepositoriesFromOwner(Owner{Login: stringPtr("JDOE"), Type: stringPtr(TargetTypeUser)})
+
+			Convey("The personal project of the user should be used", func() {
+				So(err, ShouldBeNil)
+				So(repos, ShouldHaveLength, 1)
+				So(*repos[0].FullName, ShouldEqual, "~JDOE/personal")
+			})
+		})
+	})
+}
@@ -0,0 +1,69 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"sync"
+
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// BlobKey identifies the blob that a change leaves a file with. Every secret in a blob was found when the lines
+// holding it were first added, so the same blob reached again, from another parent such as in a merge or at another
+// path such as a copied or vendored file, has nothing new to match.
+type BlobKey plumbing.Hash
+
+// NewBlobKey will create the key for a change, with a false if the change deletes the file and so has no blob
+func NewBlobKey(change *object.Change) (BlobKey, bool) {
+	hash := change.To.TreeEntry.Hash
+	return BlobKey(hash), !hash.IsZero()
+}
+
+// BlobMatch is a signature that matched a blob along with the secrets and where they were found
+type BlobMatch struct {
+	Signature Signature
+	Matches   map[string]MatchPosition
+}
+
+// BlobCache holds the signature matches of the blobs that have been scanned so identical blobs are only
+// matched once. A cache is used for each repository, or one is shared by every repository in a session.
+type BlobCache struct {
+	sync.Mutex
+	blobs map[BlobKey][]BlobMatch
+}
+
+// NewBlobCache will create an empty cache of blob matches
+func NewBlobCache() *BlobCache {
+	return &BlobCache{blobs: make(map[BlobKey][]BlobMatch)}
+}
+
+// Get will return the matches of a blob that has already been scanned, and false if it has not been scanned
+func (c *BlobCache) Get(key BlobKey) ([]BlobMatch, bool) {
+	c.Lock()
+	defer c.Unlock()
+	matches, ok := c.blobs[key]
+	return matches, ok
+}
+
+// Add will save the matches of a blob, a blob without any matches is saved so it is not scanned again
+func (c *BlobCache) Add(key BlobKey, matches []BlobMatch) {
+	c.Lock()
+	defer c.Unlock()
+	c.blobs[key] = matches
+}
+
+// AddFindingCommit will record another commit that a finding is present in. The commit of the finding itself
+// is the first one it was found in and is not added again.
+func (s *Session) AddFindingCommit(finding *Finding, commitHash string) {
+	s.Lock()
+	defer s.Unlock()
+	if commitHash == finding.CommitHash {
+		return
+	}
+	for _, c := range finding.Commits {
+		if c == commitHash {
+			return
+		}
+	}
+	finding.Commits = append(finding.Commits, commitHash)
+}
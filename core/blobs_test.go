@@ -0,0 +1,93 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+func TestBlobCache(t *testing.T) {
+
+	Convey("Given a blob cache", t, func() {
+		cache := core.NewBlobCache()
+		key := core.BlobKey(plumbing.NewHash("47c3e63a4f5d1dfae2abf4db992bbec46a96ac09"))
+
+		Convey("When a change has not been scanned", func() {
+			_, ok := cache.Get(key)
+
+			Convey("It should not be found", func() {
+				So(ok, ShouldBeFalse)
+			})
+		})
+
+		Convey("When a change without any matches has been scanned", func() {
+			cache.Add(key, nil)
+			matches, ok := cache.Get(key)
+
+			Convey("It should be found so it is not scanned again", func() {
+				So(ok, ShouldBeTrue)
+				So(matches, ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestNewBlobKey(t *testing.T) {
+
+	Convey("Given a repo where a file is copied and then deleted", t, func() {
+		repo, _ := git.Init(memory.NewStorage(), memfs.New())
+		wt, _ := repo.Worktree()
+		first := commitFile(wt, "config.yaml", "key: value\n")
+		copied := commitFile(wt, "vendor/config.yaml", "key: value\n")
+		_, _ = wt.Remove("config.yaml")
+		deleted, _ := wt.Commit("delete", &git.CommitOptions{Author: &object.Signature{Name: "wraith", When: time.Now()}})
+
+		changes := func(hash plumbing.Hash) object.Changes {
+			commit, _ := repo.CommitObject(hash)
+			changes, _ := core.GetChanges(commit, repo)
+			return changes
+		}
+
+		Convey("When the keys of the changes are created", func() {
+			firstKey, firstOk := core.NewBlobKey(changes(first)[0])
+			copiedKey, copiedOk := core.NewBlobKey(changes(copied)[0])
+			_, deletedOk := core.NewBlobKey(changes(deleted)[0])
+
+			Convey("The same blob at another path should have the same key", func() {
+				So(firstOk, ShouldBeTrue)
+				So(copiedOk, ShouldBeTrue)
+				So(copiedKey, ShouldEqual, firstKey)
+			})
+
+			Convey("A deleted file should not have a key", func() {
+				So(deletedOk, ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func TestAddFindingCommit(t *testing.T) {
+
+	Convey("Given a finding", t, func() {
+		sess := &core.Session{}
+		finding := &core.Finding{CommitHash: "abc123"}
+
+		Convey("When later commits with the same change are added", func() {
+			sess.AddFindingCommit(finding, "def456")
+			sess.AddFindingCommit(finding, "def456")
+			sess.AddFindingCommit(finding, "abc123")
+
+			Convey("Each commit should only be added once", func() {
+				So(finding.Commits, ShouldResemble, []string{"def456"})
+			})
+		})
+	})
+}
@@ -0,0 +1,236 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// These are the entropy models a signature can use to calculate the entropy of a string
+const (
+	EntropyModelShannon = "shannon" // every character of the string counts, this is the default
+	EntropyModelBase64  = "base64"  // only the characters used by base64 count
+	EntropyModelHex     = "hex"     // only the characters used by hex count
+)
+
+// The charsets of the entropy models and the thresholds and minimum lengths used by entropy signatures when they
+// are not set, these are the same as truffleHog uses.
+const (
+	Base64Charset = base64Digits + "="
+	HexCharset    = "0123456789abcdefABCDEF"
+
+	DefaultBase64Entropy   = 4.5
+	DefaultBase64MinLength = 20
+	DefaultHexEntropy      = 3.0
+	DefaultHexMinLength    = 20
+)
+
+// base64Digits are the characters of base64 without the padding, an = anywhere else is taken to separate a key
+// from its value such as in TOKEN=<secret>
+const base64Digits = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+// charsetEntropy is the Shannon entropy of the characters of a string that are in a charset, the other characters
+// still count towards the length of the string so they lower the entropy.
+func charsetEntropy(s string, charset string) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	m := map[rune]float64{}
+	for _, r := range s {
+		if strings.ContainsRune(charset, r) {
+			m[r]++
+		}
+	}
+	l := float64(len(s))
+	var res float64
+	for _, c := range m {
+		p := c / l
+		res -= p * math.Log2(p)
+	}
+	return res
+}
+
+// modelEntropy will calculate the entropy of a string with the given entropy model
+func modelEntropy(s string, model string) float64 {
+	switch model {
+	case EntropyModelBase64:
+		return charsetEntropy(s, Base64Charset)
+	case EntropyModelHex:
+		return charsetEntropy(s, HexCharset)
+	default:
+		return getEntropyInt(s)
+	}
+}
+
+// entropyModelName will check the entropy model of a signature, an unknown model is reported and the default is used
+func entropyModelName(model string, sess *Session) string {
+	switch m := strings.ToLower(model); m {
+	case "", EntropyModelShannon:
+		return EntropyModelShannon
+	case EntropyModelBase64, EntropyModelHex:
+		return m
+	default:
+		if sess.Out != nil {
+			sess.Out.Warn("Unknown entropy model %s, using %s\n", model, EntropyModelShannon)
+		}
+		return EntropyModelShannon
+	}
+}
+
+// inCharset will check if the byte at i in s is one of the characters of a charset
+func inCharset(s string, i int, charset string) bool {
+	return i >= 0 && i < len(s) && strings.IndexByte(charset, s[i]) >= 0
+}
+
+// entropyCharset is a charset that an entropy signature looks for high entropy strings of
+type entropyCharset struct {
+	model     string
+	words     *regexp.Regexp // The runs of characters in the charset that are long enough
+	threshold float64
+}
+
+// EntropySignature holds the information about an entropy signature, which finds strings of base64 or hex
+// characters with a high entropy within the content of a file without needing a regex for them
+type EntropySignature struct {
+	comment         string
+	description     string
+	enable          int
+	confidenceLevel int
+	part            string
+	signatureid     string
+	charsets        []entropyCharset
+}
+
+// newEntropySignature will create an entropy signature from its definition. The entropy model selects whether
+// base64 or hex strings are looked for, both are when it is not set.
+func newEntropySignature(def SignatureDef, sess *Session) EntropySignature {
+	s := EntropySignature{
+		comment:         def.Comment,
+		description:     def.Description,
+		enable:          def.Enable,
+		confidenceLevel: def.ConfidenceLevel,
+		part:            PartContent,
+		signatureid:     def.SignatureID,
+	}
+
+	model := strings.ToLower(def.EntropyModel)
+	if model != "" && model != EntropyModelBase64 && model != EntropyModelHex {
+		if sess.Out != nil {
+			sess.Out.Warn("Unknown entropy model %s for entropy signature %s, using %s and %s\n",
+				def.EntropyModel, def.SignatureID, EntropyModelHex, EntropyModelBase64)
+		}
+		model = ""
+	}
+
+	// Hex strings are looked for first as they are also base64 strings
+	if model == "" || model == EntropyModelHex {
+		s.charsets = append(s.charsets, newEntropyCharset(EntropyModelHex, HexCharset,
+			def.HexEntropy, DefaultHexEntropy, def.HexMinLength, DefaultHexMinLength))
+	}
+	if model == "" || model == EntropyModelBase64 {
+		s.charsets = append(s.charsets, newEntropyCharset(EntropyModelBase64, Base64Charset,
+			def.Base64Entropy, DefaultBase64Entropy, def.Base64MinLength, DefaultBase64MinLength))
+	}
+	return s
+}
+
+// newEntropyCharset will create the charset of an entropy model, using the default threshold and minimum length
+// when they are not set
+func newEntropyCharset(model, charset string, threshold, defaultThreshold float64, minLength, defaultMinLength int) entropyCharset {
+	if threshold <= 0 {
+		threshold = defaultThreshold
+	}
+	if minLength <= 0 {
+		minLength = defaultMinLength
+	}
+	words := "[" + regexp.QuoteMeta(strings.TrimSuffix(charset, "=")) + "]{" + strconv.Itoa(minLength) + ",}"
+	if model == EntropyModelBase64 {
+		words += "={0,2}"
+	}
+	return entropyCharset{
+		model:     model,
+		words:     regexp.MustCompile(words),
+		threshold: threshold,
+	}
+}
+
+// ExtractMatch will find the high entropy strings within the content of the file
+func (s EntropySignature) ExtractMatch(file MatchFile, sess *Session, change *object.Change) (bool, map[string]MatchPosition) {
+	return extractContentMatch(s, file, sess, change)
+}
+
+// matchContent will find the strings in each charset of the signature that have a higher entropy than its
+// threshold, along with where they were found and the lines around them
+func (s EntropySignature) matchContent(content string, contextLines int) (bool, map[string]MatchPosition) {
+	results := make(map[string]MatchPosition)
+
+	linesOfScannedFile := strings.Split(content, "\n")
+	lines := newLineIndex(content)
+	var context []string
+	if contextLines > 0 {
+		context = contentLines(content)
+	}
+
+	// Hex strings are also base64 strings, so a hex string is only checked when it is not part of a longer base64
+	// string and it is not checked again as base64. Base64 strings only end with an =, so one that is followed by
+	// a hex string, such as TOKEN=<hex>, does not make the hex string part of it.
+	checked := make(map[[2]int]bool)
+	i := 0
+	for _, charset := range s.charsets {
+		for _, loc := range charset.words.FindAllStringIndex(content, -1) {
+			start, end := loc[0], loc[1]
+			if checked[[2]int{start, end}] {
+				continue
+			}
+			if charset.model == EntropyModelHex && (inCharset(content, start-1, base64Digits) || inCharset(content, end, base64Digits)) {
+				continue
+			}
+			checked[[2]int{start, end}] = true
+
+			thisMatch := content[start:end]
+			if modelEntropy(thisMatch, charset.model) < charset.threshold {
+				continue
+			}
+
+			pos := newMatchPosition(content, lines, linesOfScannedFile, start, end)
+			pos.setContext(context, contextLines)
+
+			// The line has been marked as a known false positive
+			if hasAllowMarker(linesOfScannedFile, pos.StartLine) {
+				continue
+			}
+			results[strconv.Itoa(i)+"_"+thisMatch] = pos
+			i++
+		}
+	}
+	return len(results) > 0, results
+}
+
+// Enable sets whether as signature is active or not
+func (s EntropySignature) Enable() int {
+	return s.enable
+}
+
+// ConfidenceLevel sets the confidence level of the signature
+func (s EntropySignature) ConfidenceLevel() int {
+	return s.confidenceLevel
+}
+
+// Part sets the part of the file/path that is matched [ path filename extension content ]
+func (s EntropySignature) Part() string {
+	return s.part
+}
+
+// Description sets the user-facing description of the signature
+func (s EntropySignature) Description() string {
+	return s.description
+}
+
+// SignatureID sets the id used to identify the signature
+func (s EntropySignature) SignatureID() string {
+	return s.signatureid
+}
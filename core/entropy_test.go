@@ -0,0 +1,100 @@
+package core_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const entropySignatures = `Meta:
+  Version: "0.0.1"
+PatternSignatures:
+  - description: "Token"
+    enable: 1
+    match: "token = (?P<secret>\\S+)"
+    entropy: 3.5
+    entropy-model: "hex"
+    confidence-level: 3
+    part: "partcontent"
+    signatureid: "token"
+EntropySignatures:
+  - description: "High entropy string"
+    enable: 1
+    confidence-level: 3
+    signatureid: "high-entropy"
+  - description: "High entropy hex string"
+    enable: 1
+    confidence-level: 3
+    signatureid: "high-entropy-hex"
+    entropy-model: "hex"
+`
+
+const entropyContent = `aws_secret = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+sha = da39a3ee5e6b4b0d3255bfef95601890afd80709
+name = ThisIsNotASecretAtAllReally
+id = 0123012301230123012301
+token = da39a3ee5e6b4b0d3255bfef95601890afd80709
+token = ThisIsNotASecretAtAllReally
+TOKEN=9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+export API_KEY=wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY
+`
+
+func TestEntropySignatures(t *testing.T) {
+
+	Convey("Given the entropy signatures", t, func() {
+		dir, _ := ioutil.TempDir("", "wraith")
+		defer os.RemoveAll(dir)
+
+		sigPath := filepath.Join(dir, "signatures.yaml")
+		_ = ioutil.WriteFile(sigPath, []byte(entropySignatures), 0644)
+		path := filepath.Join(dir, "config.ini")
+		_ = ioutil.WriteFile(path, []byte(entropyContent), 0644)
+
+		sess := &core.Session{ScanType: "localPath"}
+		signatures := core.LoadSignatures(sigPath, 0, sess)
+
+		Convey("When both charsets are looked for", func() {
+			matched, matches := signatures[1].ExtractMatch(core.MatchFile{Path: path}, sess, nil)
+
+			Convey("Only the base64 and hex strings above their thresholds should match", func() {
+				So(matched, ShouldBeTrue)
+				So(matches, ShouldHaveLength, 5)
+				So(matches["0_da39a3ee5e6b4b0d3255bfef95601890afd80709"], ShouldResemble, core.MatchPosition{StartLine: 2, StartColumn: 7, EndLine: 2, EndColumn: 47, Line: "sha = da39a3ee5e6b4b0d3255bfef95601890afd80709"})
+				So(matches["1_da39a3ee5e6b4b0d3255bfef95601890afd80709"].StartLine, ShouldEqual, 5)
+				So(matches["3_wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"], ShouldResemble, core.MatchPosition{StartLine: 1, StartColumn: 15, EndLine: 1, EndColumn: 55, Line: `aws_secret = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"`})
+			})
+		})
+
+		Convey("When a secret is the value of a key", func() {
+			_, matches := signatures[1].ExtractMatch(core.MatchFile{Path: path}, sess, nil)
+
+			Convey("Only the value should be the secret", func() {
+				So(matches["2_9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"].StartLine, ShouldEqual, 7)
+				So(matches["4_wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"].StartLine, ShouldEqual, 8)
+			})
+		})
+
+		Convey("When only the hex charset is looked for", func() {
+			_, matches := signatures[2].ExtractMatch(core.MatchFile{Path: path}, sess, nil)
+
+			Convey("The base64 string should not match", func() {
+				So(matches, ShouldHaveLength, 3)
+				So(matches, ShouldContainKey, "0_da39a3ee5e6b4b0d3255bfef95601890afd80709")
+			})
+		})
+
+		Convey("When a pattern signature has an entropy model", func() {
+			_, matches := signatures[0].ExtractMatch(core.MatchFile{Path: path}, sess, nil)
+
+			Convey("The entropy of the secret should be calculated with the model", func() {
+				So(matches, ShouldHaveLength, 1)
+				So(matches, ShouldContainKey, "0_da39a3ee5e6b4b0d3255bfef95601890afd80709")
+			})
+		})
+	})
+}
@@ -3,58 +3,148 @@ package core
 
 import (
 	"crypto/sha1"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
+// RedactMask replaces the part of a secret that is not shown when secrets are hidden or redacted
+const RedactMask = "****"
+
 // Finding is a secret that has been discovered within a target by a discovery method
 type Finding struct {
-	Action           string
-	Content          string
-	CommitAuthor     string
-	CommitHash       string
-	CommitMessage    string
-	CommitURL        string
-	Description      string
-	FilePath         string
-	FileURL          string
-	WraithVersion    string
-	Hash             string
-	LineNumber       string
-	RepositoryName   string
-	RepositoryOwner  string
-	RepositoryURL    string
-	SignatureID      string
-	signatureVersion string
-	SecretID         string
+	Action            string
+	Content           string
+	CommitAuthor      string
+	CommitHash        string
+	Commits           []string // Later commits that contain the same change, the CommitHash is the first one
+	CommitMessage     string
+	ColumnNumber      int // The column the secret starts at, 0 if the secret is not in the content of the file
+	CommitURL         string
+	ContextAfter      []string // The lines after the secret, these are only kept if context lines are asked for
+	ContextBefore     []string // The lines before the secret
+	Description       string
+	EndColumnNumber   int // The column just after the end of the secret
+	EndLineNumber     int // The line the secret ends on, this is only different from LineNumber for multi-line secrets
+	FilePath          string
+	FileURL           string
+	Fingerprint       string
+	WraithVersion     string
+	Hash              string
+	IntroducedCommit  string // The first commit the secret was added in
+	LastPresentCommit string // The last commit the secret was present in, this is HEAD if it has not been removed
+	Line              string // The line the secret is on, the secret is masked when secrets are hidden or redacted
+	LineNumber        string
+	PresentAtHead     bool
+	PullRequest       string
+	PullRequestURL    string
+	RepositoryName    string
+	RepositoryOwner   string
+	RemovedCommit     string // The commit that removed the secret, empty if it is still present
+	RepositoryURL     string
+	SecretHash        string
+	SignatureID       string
+	signatureVersion  string
+	gistURL           string
+	secret            string // The secret is kept until the history of it has been worked out, even if it is hidden
+	snippetURL        string
+	SecretID          string // Deprecated: random and not stable across runs, use Fingerprint
+}
+
+// newFinding will create a finding for a match of a signature in a file. The key of the match holds the secret and
+// the position is where it was found. The commit is nil when the file is not from the history of a repo, such as a
+// staged file.
+func newFinding(sess *Session, signature Signature, key string, pos MatchPosition, filePath string, action string, commit *object.Commit) *Finding {
+	f := &Finding{
+		Action:           action,
+		Content:          strings.SplitAfterN(key, "_", 2)[1],
+		Description:      signature.Description(),
+		FilePath:         filePath,
+		WraithVersion:    sess.WraithVersion,
+		LineNumber:       strconv.Itoa(pos.StartLine),
+		ColumnNumber:     pos.StartColumn,
+		EndLineNumber:    pos.EndLine,
+		EndColumnNumber:  pos.EndColumn,
+		Line:             pos.Line,
+		ContextBefore:    pos.Before,
+		ContextAfter:     pos.After,
+		SignatureID:      signature.SignatureID(),
+		signatureVersion: sess.SignatureVersion,
+	}
+	if commit != nil {
+		f.CommitAuthor = commit.Author.String()
+		f.CommitHash = commit.Hash.String()
+		f.CommitMessage = strings.TrimSpace(commit.Message)
+	}
+	return f
 }
 
 // setupUrls will set the urls used to search through either github or gitlab for inclusion in the finding data
 func (f *Finding) setupUrls(sess *Session) {
+	// Gists live outside of the owner's repositories and are linked to by revision
+	if f.gistURL != "" {
+		f.RepositoryURL = f.gistURL
+		f.CommitURL = fmt.Sprintf("%s/%s", f.gistURL, f.CommitHash)
+		f.FileURL = fmt.Sprintf("%s#file-%s", f.CommitURL, gistFileAnchor(f.FilePath))
+		return
+	}
+
+	// Snippets have no page for a revision so the file links to the raw content at the commit
+	if f.snippetURL != "" {
+		f.RepositoryURL = f.snippetURL
+		f.CommitURL = f.snippetURL
+		f.FileURL = fmt.Sprintf("%s/raw/%s/%s", f.snippetURL, f.CommitHash, f.FilePath)
+		return
+	}
+
 	baseURL := ""
 	if sess.ScanType == "github-enterprise" {
 		baseURL = sess.GithubEnterpriseURL
 	} else if sess.ScanType == "github" {
 		baseURL = "https://github.com"
 	} else {
-		baseURL = "https://gitlab.com"
+		baseURL = sess.GitlabURL
 	}
 	switch sess.ScanType {
-	case "github":
+	case "github", "github-enterprise":
 		f.RepositoryURL = fmt.Sprintf("%s/%s/%s", baseURL, f.RepositoryOwner, f.RepositoryName)
 		f.FileURL = fmt.Sprintf("%s/blob/%s/%s", f.RepositoryURL, f.CommitHash, f.FilePath)
 		f.CommitURL = fmt.Sprintf("%s/commit/%s", f.RepositoryURL, f.CommitHash)
+		if f.PullRequest != "" {
+			f.PullRequestURL = fmt.Sprintf("%s/pull/%s", f.RepositoryURL, f.PullRequest)
+		}
 	case "gitlab":
 		results := CleanURLSpaces(f.RepositoryOwner, f.RepositoryName)
 		f.RepositoryURL = fmt.Sprintf("%s/%s/%s", baseURL, results[0], results[1])
 		f.FileURL = fmt.Sprintf("%s/blob/%s/%s", f.RepositoryURL, f.CommitHash, f.FilePath)
 		f.CommitURL = fmt.Sprintf("%s/commit/%s", f.RepositoryURL, f.CommitHash)
+	case "bitbucket":
+		f.RepositoryURL = fmt.Sprintf("%s/%s/%s", sess.BitbucketURL, f.RepositoryOwner, f.RepositoryName)
+		f.FileURL = fmt.Sprintf("%s/src/%s/%s", f.RepositoryURL, f.CommitHash, f.FilePath)
+		f.CommitURL = fmt.Sprintf("%s/commits/%s", f.RepositoryURL, f.CommitHash)
+	case "bitbucket-server":
+		f.RepositoryURL = fmt.Sprintf("%s/projects/%s/repos/%s", sess.BitbucketURL, f.RepositoryOwner, f.RepositoryName)
+		f.FileURL = fmt.Sprintf("%s/browse/%s?at=%s", f.RepositoryURL, f.FilePath, f.CommitHash)
+		f.CommitURL = fmt.Sprintf("%s/commits/%s", f.RepositoryURL, f.CommitHash)
 	}
 
 }
 
+// gistAnchorChars are the characters of a file name that github replaces in the anchor of a file within a gist
+var gistAnchorChars = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// gistFileAnchor will create the anchor github uses to link to a file within a gist
+func gistFileAnchor(filePath string) string {
+	return strings.ToLower(gistAnchorChars.ReplaceAllString(filePath, "-"))
+}
+
 // generateID will create an ID for each finding based up the SHA1 of discrete data points associated
 // with the finding.
 func generateID() string {
@@ -70,7 +160,87 @@ func generateID() string {
 	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
-// Initialize will set the urls and create an ID for inclusion within the finding
+// normalizeSecret will strip the whitespace and quotes that surround a secret so the same secret
+// generates the same hash regardless of how it was matched.
+func normalizeSecret(secret string) string {
+	return strings.Trim(strings.TrimSpace(secret), "\"'`")
+}
+
+// HashSecret will generate a SHA256 of the normalized secret. This allows a secret to be correlated
+// across findings without having to store the secret itself.
+func HashSecret(secret string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(normalizeSecret(secret))))
+}
+
+// GenerateFingerprint will create a stable ID for a finding based on the SHA256 of the data points that
+// identify it. The same secret in the same place will always have the same fingerprint across runs.
+func GenerateFingerprint(repository string, filePath string, signatureID string, secretHash string, commitHash string) string {
+	h := sha256.New()
+	for _, part := range []string{repository, filePath, signatureID, secretHash, commitHash} {
+		_, _ = io.WriteString(h, part)
+		_, _ = io.WriteString(h, "\x00") // keep the fields from running together
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// Initialize will set the urls and create the IDs for inclusion within the finding. The content is
+// hashed before it is hidden so hidden secrets can still be tracked.
 func (f *Finding) Initialize(sess *Session) {
 	f.setupUrls(sess)
+
+	f.SecretHash = HashSecret(f.Content)
+	f.Fingerprint = GenerateFingerprint(f.RepositoryOwner+"/"+f.RepositoryName, f.FilePath, f.SignatureID, f.SecretHash, f.CommitHash)
+
+	if sess.LegacySecretID {
+		f.SecretID = generateID()
+	}
+
+	// Destroy the secret by zeroing the content if the flag is set, or only keep enough of it to tell
+	// findings apart if it is being redacted
+	f.secret = f.Content
+	if sess.HideSecrets {
+		f.Content = ""
+		f.maskLines(RedactMask)
+	} else if sess.RedactSecrets {
+		f.Content = RedactSecret(f.secret, sess.RedactPrefix, sess.RedactSuffix)
+		f.maskLines(f.Content)
+	}
+}
+
+// maskLines will mask the secret in the line it is on and the lines of context around it
+func (f *Finding) maskLines(mask string) {
+	f.Line = maskSecret(f.Line, f.secret, mask)
+	f.ContextBefore = maskSecretLines(f.ContextBefore, f.secret, mask)
+	f.ContextAfter = maskSecretLines(f.ContextAfter, f.secret, mask)
+}
+
+// RedactSecret will mask a secret, keeping the given number of characters from the start and end of it so
+// it can still be recognized. If that would leave less than half of the secret masked only the mask is used.
+func RedactSecret(secret string, prefix int, suffix int) string {
+	r := []rune(secret)
+	if prefix < 0 || suffix < 0 || (prefix+suffix)*2 > len(r) {
+		return RedactMask
+	}
+	return string(r[:prefix]) + RedactMask + string(r[len(r)-suffix:])
+}
+
+// maskSecret will replace a secret wherever it is found in some text
+func maskSecret(text string, secret string, mask string) string {
+	if secret == "" {
+		return text
+	}
+	return strings.ReplaceAll(text, secret, mask)
+}
+
+// maskSecretLines will mask a secret in each of the lines, the lines can be shared by other matches so
+// new lines are returned rather than changing them
+func maskSecretLines(lines []string, secret string, mask string) []string {
+	if len(lines) == 0 {
+		return lines
+	}
+	masked := make([]string, len(lines))
+	for i, line := range lines {
+		masked[i] = maskSecret(line, secret, mask)
+	}
+	return masked
 }
@@ -4,13 +4,20 @@ package core
 import (
 	"context"
 	"crypto/tls"
-	"errors"
 	"fmt"
+	"hash/fnv"
+	"math"
 	"net/http"
 	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/go-github/github"
+	"github.com/sergi/go-diff/diffmatchpatch"
 	"golang.org/x/oauth2"
 	"gopkg.in/src-d/go-git.v4"
 	"gopkg.in/src-d/go-git.v4/plumbing"
@@ -40,12 +47,14 @@ type GithubRepository struct {
 
 // CloneConfiguration holds the configurations for cloning a repo
 type CloneConfiguration struct {
-	InMemClone *bool
-	URL        *string
-	Username   *string
-	Token      *string
-	Branch     *string
-	Depth      *int
+	AllRefs      *bool
+	InMemClone   *bool
+	PullRequests *bool
+	URL          *string
+	Username     *string
+	Token        *string
+	Branch       *string
+	Depth        *int
 }
 
 // Owner holds the info that we want for a repo owner
@@ -74,6 +83,17 @@ type Repository struct {
 	DefaultBranch *string
 	Description   *string
 	Homepage      *string
+	Gist          bool // the repository backs a gist rather than being a project
+	Snippet       bool // the repository backs a gitlab snippet rather than being a project
+}
+
+// hashID will create a stable numeric ID from a string ID. Some providers use UUIDs or hex strings to
+// identify things and IDs from different kinds of objects can overlap, so these are hashed to keep them unique.
+func hashID(id string) *int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(id))
+	i := int64(h.Sum64() & math.MaxInt64)
+	return &i
 }
 
 // EmptyTreeCommit is a dummy commit id used as a placeholder and for testing
@@ -98,42 +118,148 @@ func getParentCommit(commit *object.Commit, repo *git.Repository) (*object.Commi
 	return parentCommit, nil
 }
 
-// GetRepositoryHistory gets the commit history of a repository
-func GetRepositoryHistory(repository *git.Repository) ([]*object.Commit, error) {
-	var commits []*object.Commit
-	ref, err := repository.Head()
+// GetRepositoryHistory gets the commit history of a repository. By default this is the history of HEAD,
+// if refs are given it is the union of their histories with each commit only being returned once. The children
+// of a commit always come before it.
+func GetRepositoryHistory(repository *git.Repository, refs ...plumbing.Hash) ([]*object.Commit, error) {
+	if len(refs) == 0 {
+		ref, err := repository.Head()
+		if err != nil {
+			return nil, err
+		}
+		refs = []plumbing.Hash{ref.Hash()}
+	}
+	return walkHistory(repository, refs, nil)
+}
+
+// GetScanRefs will get the commits at the tip of each ref that should be scanned. HEAD is always scanned, with
+// allRefs every branch and tag is added. Branches and tags can also be selected with glob patterns, a kind of ref
+// that has patterns given only has the refs that match them added.
+func GetScanRefs(repository *git.Repository, allRefs bool, branches []string, tags []string) ([]plumbing.Hash, error) {
+	head, err := repository.Head()
 	if err != nil {
 		return nil, err
 	}
-	cIter, err := repository.Log(&git.LogOptions{From: ref.Hash()})
+	refs := []plumbing.Hash{head.Hash()}
+
+	iter, err := repository.References()
 	if err != nil {
 		return nil, err
 	}
-	_ = cIter.ForEach(func(c *object.Commit) error {
-		commits = append(commits, c)
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		name := ref.Name()
+		switch {
+		case name.IsBranch() || name.IsRemote():
+			// Cloned branches are remote refs, the remote name is not part of the branch name
+			short := name.Short()
+			if name.IsRemote() {
+				short = strings.SplitN(short, "/", 2)[1]
+			}
+			if !refSelected(short, allRefs, branches) {
+				return nil
+			}
+			refs = append(refs, ref.Hash())
+		case name.IsTag():
+			if !refSelected(name.Short(), allRefs, tags) {
+				return nil
+			}
+
+			// Annotated tags point at a tag object rather than a commit, and tags of anything but commits are skipped
+			hash := ref.Hash()
+			if tag, err := repository.TagObject(hash); err == nil {
+				commit, err := tag.Commit()
+				if err != nil {
+					return nil
+				}
+				hash = commit.Hash
+			}
+			refs = append(refs, hash)
+		}
 		return nil
 	})
-	return commits, nil
+	return refs, err
+}
+
+// refSelected will check if a ref should be scanned based on the glob patterns for its kind
+func refSelected(name string, allRefs bool, patterns []string) bool {
+	if len(patterns) == 0 {
+		return allRefs
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRepositoryHistorySince gets the commit history of a repository, excluding any commit that is reachable
+// from the since commits, which are the tips of the refs from the last scan. The history behind them is not
+// walked. If none of the since commits can be found in the repository, the full history is returned along with
+// a false so the caller knows the cursor was not used.
+func GetRepositoryHistorySince(repository *git.Repository, since []string, refs ...plumbing.Hash) ([]*object.Commit, bool, error) {
+	if len(refs) == 0 {
+		ref, err := repository.Head()
+		if err != nil {
+			return nil, false, err
+		}
+		refs = []plumbing.Hash{ref.Hash()}
+	}
+
+	var excluded []plumbing.Hash
+	for _, hash := range since {
+		if commit, err := repository.CommitObject(plumbing.NewHash(hash)); err == nil {
+			excluded = append(excluded, commit.Hash)
+		}
+	}
+
+	history, err := walkHistory(repository, refs, excluded)
+	return history, len(excluded) > 0, err
+}
+
+// GetRepositoryIdentity will get an owner and name for a local repository that are the same on every machine, so
+// fingerprints do not depend on where the repository is checked out. They come from the url of the origin remote,
+// such as org and repo for git@github.com:org/repo.git, or the name of the directory when there is no origin.
+func GetRepositoryIdentity(repository *git.Repository, root string) (string, string) {
+	if remote, err := repository.Remote(git.DefaultRemoteName); err == nil && len(remote.Config().URLs) > 0 {
+		url := strings.TrimSuffix(strings.TrimSuffix(remote.Config().URLs[0], "/"), ".git")
+		parts := strings.FieldsFunc(url, func(r rune) bool { return r == '/' || r == ':' })
+		if len(parts) >= 2 {
+			return parts[len(parts)-2], parts[len(parts)-1]
+		}
+		if len(parts) == 1 {
+			return "", parts[0]
+		}
+	}
+	return "", strings.TrimSuffix(filepath.Base(root), ".git")
 }
 
 // GetChanges will get the changes between to specific commits. It grabs the parent commit of
-// the one being passed and uses that to fetch the tree for that commit. If no commit is found,
-// it will create a fake on. It then takes that parent tree along with the tree for the commit
+// the one being passed and uses that to fetch the tree for that commit. If there is no parent
+// commit, an empty tree is used instead. It then takes that parent tree along with the tree for the commit
 // passed in and does a diff
 func GetChanges(commit *object.Commit, repo *git.Repository) (object.Changes, error) {
-	parentCommit, err := getParentCommit(commit, repo)
-	if err != nil {
-		return nil, err
-	}
-
 	commitTree, err := commit.Tree()
 	if err != nil {
 		return nil, err
 	}
 
-	parentCommitTree, err := parentCommit.Tree()
-	if err != nil {
-		return nil, err
+	// The first commit in a repo has no parent so everything in it is compared to an empty tree
+	var parentCommitTree *object.Tree
+	if commit.NumParents() > 0 {
+		parentCommit, err := getParentCommit(commit, repo)
+		if err != nil {
+			return nil, err
+		}
+
+		parentCommitTree, err = parentCommit.Tree()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	changes, err := object.DiffTree(parentCommitTree, commitTree)
@@ -175,33 +301,143 @@ func GetChangePath(change *object.Change) string {
 
 }
 
-// GetChangeContent will get the contents of a git change or patch.
-func GetChangeContent(change *object.Change) (result string, contentError error) {
-	//temporary response to:  https://github.com/sergi/go-diff/issues/89
-	defer func() {
-		if err := recover(); err != nil {
-			contentError = errors.New("panic occurred while retrieving change content: ")
+// ChangeAddition is a block of lines that were added by a change, along with the line the block starts on in the
+// new version of the file.
+type ChangeAddition struct {
+	Line    int
+	Content string
+}
+
+// GetChangeAdditions will get each block of lines that were added by a change. Lines that were removed or did not
+// change are skipped but still counted so the line numbers match the new version of the file.
+func GetChangeAdditions(change *object.Change) ([]ChangeAddition, error) {
+	from, to, err := change.Files()
+	if err != nil {
+		return nil, err
+	}
+	// A deleted file has nothing added to it
+	if to == nil {
+		return nil, nil
+	}
+	if binary, err := to.IsBinary(); err != nil || binary {
+		return nil, err
+	}
+
+	var fromContent, toContent string
+	if from != nil {
+		if fromContent, err = from.Contents(); err != nil {
+			return nil, err
+		}
+	}
+	if toContent, err = to.Contents(); err != nil {
+		return nil, err
+	}
+
+	var additions []ChangeAddition
+	line := 1
+	for _, d := range diffLines(fromContent, toContent) {
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			additions = append(additions, ChangeAddition{Line: line, Content: strings.Join(d.Lines, "")})
+			line += len(d.Lines)
+		case diffmatchpatch.DiffEqual:
+			line += len(d.Lines)
 		}
-	}()
-	patch, err := change.Patch()
+	}
+	return additions, nil
+}
+
+// GetChangeLines will get the lines of the file after a change, a deleted file has no lines
+func GetChangeLines(change *object.Change) ([]string, error) {
+	_, to, err := change.Files()
+	if err != nil || to == nil {
+		return nil, err
+	}
+	content, err := to.Contents()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	for _, filePatch := range patch.FilePatches() {
-		if filePatch.IsBinary() {
-			continue
+	return contentLines(content), nil
+}
+
+// lineDiff is a run of lines that were all inserted, deleted or kept the same between two versions of a file
+type lineDiff struct {
+	Type  diffmatchpatch.Operation
+	Lines []string
+}
+
+// lineDiffTimeout is how long the lines of a file are diffed for before the whole of the new file is treated as
+// added, so a large generated file or lockfile can not hold up a thread
+const lineDiffTimeout = 5 * time.Second
+
+// diffLines will work out which lines were inserted, deleted or kept between two versions of a file. Each distinct
+// line is turned into a single rune so the diff is done line by line. This is done here as the line diff in go-git
+// does not work with the version of go-diff that is used.
+func diffLines(from string, to string) []lineDiff {
+	runes := make(map[string]rune)
+	lines := make(map[rune]string)
+	encode := func(text string) ([]rune, bool) {
+		var encoded []rune
+		for _, l := range strings.SplitAfter(text, "\n") {
+			if l == "" {
+				continue
+			}
+			r, ok := runes[l]
+			if !ok {
+				// Surrogates are skipped as they can not be turned into a string and back
+				r = rune(len(lines))
+				if r >= 0xD800 {
+					r += 0x800
+				}
+				if r > utf8.MaxRune {
+					return nil, false
+				}
+				runes[l] = r
+				lines[r] = l
+			}
+			encoded = append(encoded, r)
 		}
-		for _, chunk := range filePatch.Chunks() {
-			result += chunk.Content()
+		return encoded, true
+	}
+	added := []lineDiff{{Type: diffmatchpatch.DiffInsert, Lines: strings.SplitAfter(to, "\n")}}
+	fromRunes, okFrom := encode(from)
+	toRunes, okTo := encode(to)
+	// There are too many distinct lines to diff, so the whole of the new file is treated as added
+	if !okFrom || !okTo {
+		return added
+	}
+
+	// The diff gives up on finding the smallest diff once it times out, so the whole of the new file is treated
+	// as added rather than trusting what it has found
+	dmp := diffmatchpatch.New()
+	dmp.DiffTimeout = lineDiffTimeout
+	started := time.Now()
+	result := dmp.DiffMainRunes(fromRunes, toRunes, false)
+	if time.Since(started) >= lineDiffTimeout {
+		return added
+	}
+
+	var diffs []lineDiff
+	for _, d := range result {
+		ld := lineDiff{Type: d.Type}
+		for _, r := range d.Text {
+			ld.Lines = append(ld.Lines, lines[r])
 		}
+		diffs = append(diffs, ld)
 	}
-	return result, nil
+	return diffs
 }
 
-// GatherGitlabRepositories will gather all repositories associated with a given target during a scan session.
+// GatherGitlabRepositories will gather all repositories associated with a given target during a scan session using
+// the api client of the session, so it is also used for Bitbucket.
 // This is done using threads, whose count is set via commandline flag. Care much be taken to avoid rate
 // limiting associated with suspected DOS attacks.
 func GatherGitlabRepositories(sess *Session) {
+	// None of the targets could be found so there is nothing to gather
+	if len(sess.Targets) == 0 {
+		return
+	}
+
 	var ch = make(chan *Owner, len(sess.Targets))
 	sess.Out.Debug("Number of targets: %d\n", len(sess.Targets))
 	var wg sync.WaitGroup
@@ -304,12 +540,25 @@ func (s *Session) InitGitClient() {
 		s.GithubClient = github.NewClient(tc)
 	}
 
+	if s.ScanType == "bitbucket" {
+		s.Client = bitbucketClient.NewClient(bitbucketClient{}, BitbucketAPIURL, s.BitbucketUsername, s.BitbucketAccessToken, false, s.Out)
+	}
+
+	if s.ScanType == "bitbucket-server" {
+		if _, err := url.ParseRequestURI(s.BitbucketURL); err != nil {
+			s.Out.Fatal("Unable to parse --bitbucket-server-url: <%s>\n", s.BitbucketURL)
+		}
+		s.Client = bitbucketClient.NewClient(bitbucketClient{}, s.BitbucketURL+"/rest/api/1.0", s.BitbucketUsername, s.BitbucketAccessToken, true, s.Out)
+	}
+
 	if s.ScanType == "gitlab" { // TODO need to refactor all this
 		CheckGitlabAPIToken(s.GitlabAccessToken, s) // TODO move this out
 		var err error
-		// TODO need to add in the bits to parse the url here as well
+		if _, err = url.ParseRequestURI(s.GitlabURL); err != nil {
+			s.Out.Fatal("Unable to parse --gitlab-url: <%s>\n", s.GitlabURL)
+		}
 		// TODO set this to some sort of consistent client, look to github for ideas
-		s.Client, err = gitlabClient.NewClient(gitlabClient{}, s.GitlabAccessToken, s.Out)
+		s.Client, err = gitlabClient.NewClient(gitlabClient{}, s.GitlabAccessToken, s.GitlabURL, s.Out)
 		if err != nil {
 			s.Out.Fatal("Error initializing GitLab client: %s", err)
 		}
@@ -325,25 +574,35 @@ func cloneRepository(sess *Session, repo *Repository, threadID int) (*git.Reposi
 	var path string
 	var err error
 
+	// Every branch and tag needs to be fetched if anything more than the default branch is being scanned
+	allRefs := sess.scansAllRefs()
+
+	// Gists are not repositories that can have pull requests
+	pullRequests := sess.ScanPullRequests && !repo.Gist
+
 	switch sess.ScanType {
 	case "github":
 		cloneConfig := CloneConfiguration{
-			URL:        repo.CloneURL,
-			Branch:     repo.DefaultBranch,
-			Depth:      &sess.CommitDepth,
-			InMemClone: &sess.InMemClone,
-			Token:      &sess.GithubAccessToken,
+			URL:          repo.CloneURL,
+			Branch:       repo.DefaultBranch,
+			Depth:        &sess.CommitDepth,
+			InMemClone:   &sess.InMemClone,
+			AllRefs:      &allRefs,
+			PullRequests: &pullRequests,
+			Token:        &sess.GithubAccessToken,
 		}
 		// Clone a github repo
 		clone, path, err = cloneGithub(&cloneConfig)
 
 	case "github-enterprise":
 		cloneConfig := CloneConfiguration{
-			URL:        repo.CloneURL,
-			Branch:     repo.DefaultBranch,
-			Depth:      &sess.CommitDepth,
-			InMemClone: &sess.InMemClone,
-			Token:      &sess.GithubAccessToken,
+			URL:          repo.CloneURL,
+			Branch:       repo.DefaultBranch,
+			Depth:        &sess.CommitDepth,
+			InMemClone:   &sess.InMemClone,
+			AllRefs:      &allRefs,
+			PullRequests: &pullRequests,
+			Token:        &sess.GithubAccessToken,
 		}
 		// Clone a github repo
 		clone, path, err = cloneGithub(&cloneConfig)
@@ -356,16 +615,35 @@ func cloneRepository(sess *Session, repo *Repository, threadID int) (*git.Reposi
 			Depth:      &sess.CommitDepth,
 			Token:      &sess.GitlabAccessToken, // TODO Is this need since we already have a client?
 			InMemClone: &sess.InMemClone,
+			AllRefs:    &allRefs,
 			Username:   &userName,
 		}
 		// Clone a gitlab repo
 		clone, path, err = cloneGitlab(&cloneConfig)
+	case "bitbucket", "bitbucket-server":
+		// Bitbucket Cloud access tokens are used with a fixed username rather than as a bearer token
+		userName := sess.BitbucketUsername
+		if userName == "" && sess.BitbucketAccessToken != "" && sess.ScanType == "bitbucket" {
+			userName = "x-token-auth"
+		}
+		cloneConfig := CloneConfiguration{
+			URL:        repo.CloneURL,
+			Branch:     repo.DefaultBranch,
+			Depth:      &sess.CommitDepth,
+			Token:      &sess.BitbucketAccessToken,
+			InMemClone: &sess.InMemClone,
+			AllRefs:    &allRefs,
+			Username:   &userName,
+		}
+		// Clone a bitbucket repo
+		clone, path, err = cloneBitbucket(&cloneConfig)
 	case "localGit":
 		cloneConfig := CloneConfiguration{
 			URL:        repo.CloneURL,
 			Branch:     repo.DefaultBranch,
 			Depth:      &sess.CommitDepth,
 			InMemClone: &sess.InMemClone,
+			AllRefs:    &allRefs,
 		}
 		// Clone a local repo
 		clone, path, err = cloneLocal(&cloneConfig)
@@ -379,6 +657,7 @@ func cloneRepository(sess *Session, repo *Repository, threadID int) (*git.Reposi
 			return nil, "", err
 		default:
 			sess.Out.Error("Error cloning repository %s: %s\n", *repo.CloneURL, err)
+			sess.Stats.IncrementScanFailures()
 			return nil, "", err
 		}
 	}
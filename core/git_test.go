@@ -0,0 +1,269 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+// commitFile will write a file to the worktree and commit it
+func commitFile(wt *git.Worktree, name string, content string) plumbing.Hash {
+	f, _ := wt.Filesystem.Create(name)
+	_, _ = f.Write([]byte(content))
+	_ = f.Close()
+	_, _ = wt.Add(name)
+	hash, _ := wt.Commit(name, &git.CommitOptions{Author: &object.Signature{Name: "wraith", When: time.Now()}})
+	return hash
+}
+
+func TestGetScanRefs(t *testing.T) {
+
+	Convey("Given a repo with branches and tags", t, func() {
+		repo, _ := git.Init(memory.NewStorage(), memfs.New())
+		wt, _ := repo.Worktree()
+
+		first := commitFile(wt, "a.txt", "a")
+		commitFile(wt, "b.txt", "b")
+
+		// A feature branch off of the first commit with a release tag on it
+		_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/feature", first))
+		_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/release/1", first))
+		_ = wt.Checkout(&git.CheckoutOptions{Branch: "refs/heads/feature"})
+		feature := commitFile(wt, "c.txt", "c")
+		_, _ = repo.CreateTag("v1", feature, &git.CreateTagOptions{
+			Tagger:  &object.Signature{Name: "wraith", When: time.Now()},
+			Message: "v1",
+		})
+		_ = wt.Checkout(&git.CheckoutOptions{Branch: "refs/heads/master"})
+
+		Convey("When no refs are asked for", func() {
+			refs, err := core.GetScanRefs(repo, false, nil, nil)
+			history, _ := core.GetRepositoryHistory(repo, refs...)
+
+			Convey("Only the history of HEAD should be scanned", func() {
+				So(err, ShouldBeNil)
+				So(len(refs), ShouldEqual, 1)
+				So(len(history), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When all refs are asked for", func() {
+			refs, err := core.GetScanRefs(repo, true, nil, nil)
+			history, _ := core.GetRepositoryHistory(repo, refs...)
+
+			Convey("Each commit should be scanned once", func() {
+				So(err, ShouldBeNil)
+				So(len(refs), ShouldEqual, 5)
+				So(len(history), ShouldEqual, 3)
+			})
+		})
+
+		Convey("When branches are filtered", func() {
+			refs, _ := core.GetScanRefs(repo, true, []string{"release/*"}, []string{"none"})
+			history, _ := core.GetRepositoryHistory(repo, refs...)
+
+			Convey("Only HEAD and the matching branches should be scanned", func() {
+				So(len(refs), ShouldEqual, 2)
+				So(len(history), ShouldEqual, 2)
+			})
+		})
+
+		Convey("When tags are filtered", func() {
+			refs, _ := core.GetScanRefs(repo, false, nil, []string{"v*"})
+			history, _ := core.GetRepositoryHistory(repo, refs...)
+
+			Convey("The annotated tag should be scanned from the commit it points at", func() {
+				So(len(refs), ShouldEqual, 2)
+				So(refs[1], ShouldEqual, feature)
+				So(len(history), ShouldEqual, 3)
+			})
+		})
+	})
+}
+
+func TestGetRepositoryHistorySince(t *testing.T) {
+
+	Convey("Given a repo that was scanned with a branch that was not merged", t, func() {
+		repo, _ := git.Init(memory.NewStorage(), memfs.New())
+		wt, _ := repo.Worktree()
+
+		first := commitFile(wt, "a.txt", "a")
+		second := commitFile(wt, "b.txt", "b")
+		_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/feature", first))
+		_ = wt.Checkout(&git.CheckoutOptions{Branch: "refs/heads/feature"})
+		feature := commitFile(wt, "c.txt", "c")
+		since := []string{second.String(), feature.String()}
+
+		// Both branches move on and are then merged
+		newFeature := commitFile(wt, "d.txt", "d")
+		_ = wt.Checkout(&git.CheckoutOptions{Branch: "refs/heads/master"})
+		newMaster := commitFile(wt, "e.txt", "e")
+		merge, _ := wt.Commit("merge", &git.CommitOptions{
+			Author:  &object.Signature{Name: "wraith", When: time.Now()},
+			Parents: []plumbing.Hash{newMaster, newFeature},
+		})
+
+		Convey("When the history since the tips of the last scan is read", func() {
+			history, used, err := core.GetRepositoryHistorySince(repo, since, merge)
+
+			Convey("Only the commits added to either branch should be scanned", func() {
+				So(err, ShouldBeNil)
+				So(used, ShouldBeTrue)
+				So(len(history), ShouldEqual, 3)
+				So(history[0].Hash, ShouldEqual, merge)
+			})
+		})
+
+		Convey("When none of the tips of the last scan are in the repo", func() {
+			history, used, _ := core.GetRepositoryHistorySince(repo, []string{core.EmptyTreeCommitID}, merge)
+
+			Convey("The full history should be scanned", func() {
+				So(used, ShouldBeFalse)
+				So(len(history), ShouldEqual, 6)
+			})
+		})
+
+		Convey("When the full history is read", func() {
+			history, _ := core.GetRepositoryHistory(repo, merge)
+
+			Convey("Every commit should come before its parents", func() {
+				order := make(map[plumbing.Hash]int)
+				for i, c := range history {
+					order[c.Hash] = i
+				}
+				So(len(history), ShouldEqual, 6)
+				for _, c := range history {
+					for _, parent := range c.ParentHashes {
+						So(order[c.Hash], ShouldBeLessThan, order[parent])
+					}
+				}
+			})
+		})
+	})
+}
+
+func TestGetRepositoryIdentity(t *testing.T) {
+
+	Convey("Given a local repo", t, func() {
+		repo, _ := git.Init(memory.NewStorage(), memfs.New())
+
+		Convey("When it has no origin", func() {
+			owner, name := core.GetRepositoryIdentity(repo, "/srv/git/repo.git")
+
+			Convey("The name of the directory should be used", func() {
+				So(owner, ShouldEqual, "")
+				So(name, ShouldEqual, "repo")
+			})
+		})
+
+		Convey("When it has an origin", func() {
+			for _, url := range []string{"git@github.com:org/repo.git", "https://github.com/org/repo", "ssh://git@host:22/org/repo.git/"} {
+				_ = repo.DeleteRemote("origin")
+				_, _ = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{url}})
+				owner, name := core.GetRepositoryIdentity(repo, "/home/user/checkout")
+
+				Convey("The owner and name should come from "+url, func() {
+					So(owner, ShouldEqual, "org")
+					So(name, ShouldEqual, "repo")
+				})
+			}
+		})
+	})
+}
+
+func TestGetPullRequestCommits(t *testing.T) {
+
+	Convey("Given a repo with pull request refs", t, func() {
+		repo, _ := git.Init(memory.NewStorage(), memfs.New())
+		wt, _ := repo.Worktree()
+
+		first := commitFile(wt, "a.txt", "a")
+		head := commitFile(wt, "b.txt", "b")
+
+		// A pull request that was merged, and one that was never merged with a commit on top of another
+		_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/pull/1/head", head))
+		_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/pr", first))
+		_ = wt.Checkout(&git.CheckoutOptions{Branch: "refs/heads/pr"})
+		unmerged := commitFile(wt, "c.txt", "c")
+		_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/pull/2/head", unmerged))
+		onTop := commitFile(wt, "d.txt", "d")
+		_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/pull/3/head", onTop))
+		_ = wt.Checkout(&git.CheckoutOptions{Branch: "refs/heads/master"})
+
+		Convey("When the pull request refs are read", func() {
+			heads, err := core.GetPullRequestRefs(repo)
+
+			Convey("Each pull request should be found by number", func() {
+				So(err, ShouldBeNil)
+				So(len(heads), ShouldEqual, 3)
+				So(heads[unmerged], ShouldEqual, 2)
+			})
+
+			Convey("Only the commits that were not merged should belong to a pull request", func() {
+				commits := core.GetPullRequestCommits(repo, []plumbing.Hash{head}, heads)
+				So(len(commits), ShouldEqual, 2)
+				So(commits[unmerged], ShouldEqual, 2)
+				So(commits[onTop], ShouldEqual, 3)
+			})
+		})
+	})
+}
+
+func TestGetChanges(t *testing.T) {
+
+	Convey("Given the first commit of a repo", t, func() {
+		repo, _ := git.Init(memory.NewStorage(), memfs.New())
+		wt, _ := repo.Worktree()
+		hash := commitFile(wt, "a.txt", "one\n")
+		commit, _ := repo.CommitObject(hash)
+
+		Convey("When its changes are retrieved", func() {
+			changes, err := core.GetChanges(commit, repo)
+
+			Convey("Every file in it should be added", func() {
+				So(err, ShouldBeNil)
+				So(changes, ShouldHaveLength, 1)
+				So(core.GetChangeAction(changes[0]), ShouldEqual, "Insert")
+				So(core.GetChangePath(changes[0]), ShouldEqual, "a.txt")
+			})
+		})
+	})
+}
+
+func TestGetChangeAdditions(t *testing.T) {
+
+	Convey("Given a change that adds, removes and keeps lines", t, func() {
+		repo, _ := git.Init(memory.NewStorage(), memfs.New())
+		wt, _ := repo.Worktree()
+
+		first := commitFile(wt, "a.txt", "one\ntwo\nthree\nfour\n")
+		second := commitFile(wt, "a.txt", "one\nnew\nthree\nfour\nfive\n")
+
+		from, _ := repo.CommitObject(first)
+		to, _ := repo.CommitObject(second)
+		fromTree, _ := from.Tree()
+		toTree, _ := to.Tree()
+		changes, _ := fromTree.Diff(toTree)
+
+		Convey("When the additions are retrieved", func() {
+			additions, err := core.GetChangeAdditions(changes[0])
+
+			Convey("Only the added lines should be returned with their lines in the new file", func() {
+				So(err, ShouldBeNil)
+				So(additions, ShouldResemble, []core.ChangeAddition{
+					{Line: 2, Content: "new\n"},
+					{Line: 5, Content: "five\n"},
+				})
+			})
+		})
+	})
+}
@@ -8,16 +8,23 @@ import (
 	"io/ioutil"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/google/go-github/github"
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/config"
 	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport/http"
 	"gopkg.in/src-d/go-git.v4/storage/memory"
 )
 
+// PullRequestRefSpec fetches the head of every pull request, open or closed, into refs of the same name
+const PullRequestRefSpec = "+refs/pull/*/head:refs/pull/*/head"
+
 // cloneGithub will set the clone config and then either do a plain clone if it is going to disk
 // or a full clone if going ito memory.
 func cloneGithub(cloneConfig *CloneConfiguration) (*git.Repository, string, error) {
@@ -34,6 +41,17 @@ func cloneGithub(cloneConfig *CloneConfiguration) (*git.Repository, string, erro
 		},
 	}
 
+	// Gists do not report a default branch so we clone whatever HEAD points to
+	if *cloneConfig.Branch == "" {
+		cloneOptions.ReferenceName = ""
+	}
+
+	// Every branch and tag is fetched when more than the default branch is being scanned
+	if *cloneConfig.AllRefs {
+		cloneOptions.SingleBranch = false
+		cloneOptions.Tags = git.AllTags
+	}
+
 	var repository *git.Repository
 	var err error
 	var dir string
@@ -50,9 +68,86 @@ func cloneGithub(cloneConfig *CloneConfiguration) (*git.Repository, string, erro
 	if err != nil {
 		return nil, dir, err
 	}
+
+	// Pull requests are not branches so they have to be fetched on their own after the clone
+	if *cloneConfig.PullRequests {
+		err = repository.Fetch(&git.FetchOptions{
+			RefSpecs: []config.RefSpec{PullRequestRefSpec},
+			Depth:    *cloneConfig.Depth,
+			Auth:     cloneOptions.Auth,
+			Tags:     git.NoTags,
+		})
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			return repository, dir, err
+		}
+	}
 	return repository, dir, nil
 }
 
+// GetPullRequestRefs will get the commit at the head of each pull request that has been fetched, along with the
+// number of the pull request.
+func GetPullRequestRefs(repository *git.Repository) (map[plumbing.Hash]int, error) {
+	pullRequests := make(map[plumbing.Hash]int)
+	iter, err := repository.References()
+	if err != nil {
+		return nil, err
+	}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		parts := strings.Split(ref.Name().String(), "/")
+		if ref.Type() != plumbing.HashReference || len(parts) != 4 || parts[1] != "pull" || parts[3] != "head" {
+			return nil
+		}
+		number, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return nil
+		}
+		// The same commit can be the head of several pull requests, the first one opened is used
+		if n, ok := pullRequests[ref.Hash()]; !ok || number < n {
+			pullRequests[ref.Hash()] = number
+		}
+		return nil
+	})
+	return pullRequests, err
+}
+
+// GetPullRequestCommits will find the commits that are only reachable from a pull request, and not from any of
+// the other refs being scanned, and map them to the number of the pull request they came from. These are commits
+// that were never merged or were force pushed away.
+func GetPullRequestCommits(repository *git.Repository, refs []plumbing.Hash, pullRequests map[plumbing.Hash]int) map[plumbing.Hash]int {
+	seen := make(map[plumbing.Hash]bool)
+	for _, ref := range refs {
+		commit, err := repository.CommitObject(ref)
+		if err != nil {
+			continue
+		}
+		_ = object.NewCommitPreorderIter(commit, seen, nil).ForEach(func(c *object.Commit) error {
+			seen[c.Hash] = true
+			return nil
+		})
+	}
+
+	// The pull requests are walked in order so a commit shared by several of them belongs to the first one
+	var heads []plumbing.Hash
+	for head := range pullRequests {
+		heads = append(heads, head)
+	}
+	sort.Slice(heads, func(i, j int) bool { return pullRequests[heads[i]] < pullRequests[heads[j]] })
+
+	commits := make(map[plumbing.Hash]int)
+	for _, head := range heads {
+		commit, err := repository.CommitObject(head)
+		if err != nil {
+			continue
+		}
+		_ = object.NewCommitPreorderIter(commit, seen, nil).ForEach(func(c *object.Commit) error {
+			seen[c.Hash] = true
+			commits[c.Hash] = pullRequests[head]
+			return nil
+		})
+	}
+	return commits
+}
+
 // Client holds a github api client instance
 type githubClient struct {
 	apiClient *github.Client
@@ -156,14 +251,14 @@ func CheckGithubAPIToken(t string, sess *Session) string {
 	// check to make sure the length is proper
 	if len(t) != 40 {
 		sess.Out.Error("The token is invalid. Please use a valid Github token\n")
-		os.Exit(2)
+		os.Exit(ExitCodeError)
 	}
 
 	// match only letters and numbers and ensure you match 40
 	exp1 := regexp.MustCompile(`[A-Za-z0-9\_]{40}`)
 	if !exp1.MatchString(t) {
 		sess.Out.Error("The token is invalid. Please use a valid Github token\n")
-		os.Exit(2)
+		os.Exit(ExitCodeError)
 	}
 	return t
 }
@@ -598,3 +693,97 @@ func GatherOrgsMembersRepositories(sess *Session) {
 		}
 	}
 }
+
+// GatherGists will gather the gists of every user in the session, including the members of any orgs, so they
+// can be cloned and analyzed like any other repository. Only public gists can be listed for other users, when
+// a token is given the secret gists of the user the token belongs to are gathered as well.
+func GatherGists(sess *Session) {
+	sess.Out.Important("Gathering gists...\n")
+	ctx := context.Background()
+
+	// Gists are owned by users so the members of any orgs are added as targets
+	optMember := &github.ListMembersOptions{}
+	for _, o := range sess.Organizations {
+		optMember.Page = 1
+		for {
+			members, resp, err := sess.GithubClient.Organizations.ListMembers(ctx, *o.Login, optMember)
+			if err != nil {
+				sess.Out.Error("Unable to get org members: %s\n", err)
+				break
+			}
+			for _, member := range members {
+				sess.addUser(member)
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			optMember.Page = resp.NextPage
+		}
+	}
+
+	// Listing the gists of the authenticated user is the only way to get secret gists
+	var authLogin string
+	if sess.GithubAccessToken != "" {
+		user, _, err := sess.GithubClient.Users.Get(ctx, "")
+		if err != nil {
+			sess.Out.Debug("Unable to get the user for the token, only public gists will be gathered: %s\n", err)
+		} else {
+			authLogin = user.GetLogin()
+		}
+	}
+
+	for _, u := range sess.GithubUsers {
+		login := u.GetLogin()
+		owner := login
+		if authLogin != "" && login == authLogin {
+			owner = ""
+		}
+
+		gists, err := getGists(ctx, owner, sess.GithubClient)
+		if err != nil {
+			sess.Out.Error("Error gathering gists from %s: %s\n", login, err)
+			continue
+		}
+
+		for _, gist := range gists {
+			sess.Stats.IncrementRepositoriesTotal()
+			sess.Out.Debug(" Retrieved gist %s from user %s\n", gist.GetID(), login)
+			sess.AddRepository(gistRepository(gist, login))
+		}
+	}
+}
+
+// getGists will page through and return all the gists for a user. An empty user is the
+// authenticated user, which includes their secret gists.
+func getGists(ctx context.Context, user string, client *github.Client) ([]*github.Gist, error) {
+	var allGists []*github.Gist
+	opt := &github.GistListOptions{}
+	for {
+		gists, resp, err := client.Gists.List(ctx, user, opt)
+		if err != nil {
+			return allGists, err
+		}
+		allGists = append(allGists, gists...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return allGists, nil
+}
+
+// gistRepository will create a repository for a gist so it can be cloned and analyzed
+func gistRepository(gist *github.Gist, owner string) *Repository {
+	id := gist.GetID()
+	return &Repository{
+		Owner:         stringPtr(owner),
+		ID:            hashID("gist:" + id),
+		Name:          stringPtr(id),
+		FullName:      stringPtr(owner + "/" + id),
+		CloneURL:      stringPtr(gist.GetGitPullURL()),
+		URL:           stringPtr(gist.GetHTMLURL()),
+		DefaultBranch: stringPtr(""),
+		Description:   gist.Description,
+		Gist:          true,
+	}
+}
@@ -32,6 +32,17 @@ func cloneGitlab(cloneConfig *CloneConfiguration) (*git.Repository, string, erro
 		},
 	}
 
+	// Snippets do not report a default branch so we clone whatever HEAD points to
+	if *cloneConfig.Branch == "" {
+		cloneOptions.ReferenceName = ""
+	}
+
+	// Every branch and tag is fetched when more than the default branch is being scanned
+	if *cloneConfig.AllRefs {
+		cloneOptions.SingleBranch = false
+		cloneOptions.Tags = git.AllTags
+	}
+
 	var repository *git.Repository
 	var err error
 	var dir string
@@ -57,10 +68,11 @@ type gitlabClient struct {
 	logger    *Logger
 }
 
-// NewClient creates a gitlab api client instance using a token
-func (c gitlabClient) NewClient(token string, logger *Logger) (gitlabClient, error) {
+// NewClient creates a gitlab api client instance using a token. The base url is the root of the gitlab
+// instance, such as https://gitlab.com, and the api path is added by the client.
+func (c gitlabClient) NewClient(token string, baseURL string, logger *Logger) (gitlabClient, error) {
 	var err error
-	c.apiClient, err = gitlab.NewClient(token)
+	c.apiClient, err = gitlab.NewClient(token, gitlab.WithBaseURL(baseURL))
 	if err != nil {
 		return gitlabClient{}, err
 	}
@@ -72,10 +84,10 @@ func (c gitlabClient) NewClient(token string, logger *Logger) (gitlabClient, err
 // CheckGitlabAPIToken will ensure we have a valid github api token
 func CheckGitlabAPIToken(t string, sess *Session) string {
 
-	// check to make sure the length is proper
-	if len(t) != 20 {
+	// check to make sure the length is proper, newer tokens have a glpat- prefix
+	if len(strings.TrimPrefix(t, "glpat-")) != 20 {
 		sess.Out.Error("Gitlab token is invalid\n")
-		os.Exit(2)
+		os.Exit(ExitCodeError)
 	}
 
 	return t
@@ -340,3 +352,124 @@ func (c githubClient) GetRepositoriesFromOwner(target Owner) ([]*Repository, err
 
 	return allRepos, nil
 }
+
+// GatherGitlabSnippets will gather the personal snippets of the token owner, when they are being scanned, and
+// the snippets of every project that has been gathered. Snippets are backed by a git repository so they are
+// cloned and analyzed like any other repository.
+func GatherGitlabSnippets(sess *Session) {
+	client, ok := sess.Client.(gitlabClient)
+	if !ok {
+		return
+	}
+	sess.Out.Important("Gathering snippets...\n")
+
+	users := make(map[string]bool)
+	for _, target := range sess.Targets {
+		if *target.Type == TargetTypeUser {
+			users[*target.Login] = true
+		}
+	}
+
+	var snippets []*Repository
+	if len(users) > 0 {
+		current, _, err := client.apiClient.Users.CurrentUser()
+		if err != nil {
+			sess.Out.Error(" Failed to retrieve the owner of the token: %s\n", err)
+		} else if users[current.Username] {
+			userSnippets, err := client.getUserSnippets(current.Username)
+			if err != nil {
+				sess.Out.Error(" Failed to retrieve personal snippets: %s\n", err)
+			}
+			snippets = append(snippets, userSnippets...)
+		}
+
+		// The api has no way to list the personal snippets of anyone else, looking through every public
+		// snippet on the instance for theirs would never finish on gitlab.com
+		for user := range users {
+			if current == nil || user != current.Username {
+				sess.Out.Warn(" The personal snippets of %s can not be listed, only those of the token owner are scanned\n", user)
+			}
+		}
+	}
+
+	// The repositories are copied as the snippets are added to the same list
+	sess.Lock()
+	projects := make([]*Repository, len(sess.Repositories))
+	copy(projects, sess.Repositories)
+	sess.Unlock()
+
+	for _, project := range projects {
+		if project.Snippet || project.Gist {
+			continue
+		}
+		projectSnippets, err := client.getProjectSnippets(project)
+		if err != nil {
+			sess.Out.Error(" Failed to retrieve snippets from %s: %s\n", *project.FullName, err)
+			continue
+		}
+		snippets = append(snippets, projectSnippets...)
+	}
+
+	for _, snippet := range snippets {
+		sess.Stats.IncrementRepositoriesTotal()
+		sess.Out.Debug(" Retrieved snippet %s from %s\n", *snippet.URL, *snippet.Owner)
+		sess.AddRepository(snippet)
+	}
+	sess.Out.Info(" Retrieved %d %s\n", len(snippets), Pluralize(len(snippets), "snippet", "snippets"))
+}
+
+// getUserSnippets will gather the personal snippets of the user the token belongs to, including private ones
+func (c gitlabClient) getUserSnippets(owner string) ([]*Repository, error) {
+	var allSnippets []*Repository
+	opt := &gitlab.ListSnippetsOptions{}
+	for {
+		snippets, resp, err := c.apiClient.Snippets.ListSnippets(opt)
+		if err != nil {
+			return allSnippets, err
+		}
+		for _, snippet := range snippets {
+			allSnippets = append(allSnippets, snippetRepository(snippet, owner))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return allSnippets, nil
+}
+
+// getProjectSnippets will gather the snippets of a given project
+func (c gitlabClient) getProjectSnippets(project *Repository) ([]*Repository, error) {
+	var allSnippets []*Repository
+	opt := &gitlab.ListProjectSnippetsOptions{}
+	for {
+		snippets, resp, err := c.apiClient.ProjectSnippets.ListSnippets(int(*project.ID), opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, snippet := range snippets {
+			allSnippets = append(allSnippets, snippetRepository(snippet, *project.Owner))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return allSnippets, nil
+}
+
+// snippetRepository will create a repository for a snippet so it can be cloned and analyzed
+func snippetRepository(snippet *gitlab.Snippet, owner string) *Repository {
+	name := fmt.Sprintf("snippet-%d", snippet.ID)
+	return &Repository{
+		Owner:         gitlab.String(owner),
+		ID:            hashID("snippet:" + snippet.WebURL),
+		Name:          gitlab.String(name),
+		FullName:      gitlab.String(owner + "/" + name),
+		CloneURL:      gitlab.String(snippet.WebURL + ".git"),
+		URL:           gitlab.String(snippet.WebURL),
+		DefaultBranch: gitlab.String(""),
+		Description:   gitlab.String(snippet.Title),
+		Snippet:       true,
+	}
+}
@@ -0,0 +1,191 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"container/heap"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// historySlop is how many more commits are walked once only excluded history is left to walk, in case the commit
+// times are out of order and an excluded commit is still to come that would exclude a commit already walked
+const historySlop = 5
+
+// historyEntry is a commit waiting to be walked, the index is the order it was found in so commits with the same
+// time are always walked in the same order
+type historyEntry struct {
+	commit *object.Commit
+	index  int
+}
+
+// historyQueue is a priority queue of commits with the newest commit first
+type historyQueue []historyEntry
+
+func (q historyQueue) Len() int { return len(q) }
+
+func (q historyQueue) Less(i, j int) bool {
+	ti, tj := q[i].commit.Committer.When, q[j].commit.Committer.When
+	if ti.Equal(tj) {
+		return q[i].index < q[j].index
+	}
+	return ti.After(tj)
+}
+
+func (q historyQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *historyQueue) Push(x interface{}) { *q = append(*q, x.(historyEntry)) }
+
+func (q *historyQueue) Pop() interface{} {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// walkHistory will get the commits reachable from the refs that are not reachable from the excluded commits, in
+// topological order with the children of a commit always before it. The walk goes from the newest commit to the
+// oldest and stops once only excluded commits are left, so the history behind the excluded commits is not walked.
+func walkHistory(repository *git.Repository, refs []plumbing.Hash, excluded []plumbing.Hash) ([]*object.Commit, error) {
+	seen := make(map[plumbing.Hash]bool)
+	isExcluded := make(map[plumbing.Hash]bool)
+	walked := make(map[plumbing.Hash]bool)
+	propagated := make(map[plumbing.Hash]bool)
+	var queue historyQueue
+	index := 0
+
+	push := func(commit *object.Commit) {
+		heap.Push(&queue, historyEntry{commit: commit, index: index})
+		index++
+	}
+	for _, hash := range excluded {
+		commit, err := repository.CommitObject(hash)
+		if err != nil {
+			continue
+		}
+		seen[hash] = true
+		isExcluded[hash] = true
+		push(commit)
+	}
+	for _, hash := range refs {
+		if seen[hash] {
+			continue
+		}
+		commit, err := repository.CommitObject(hash)
+		if err != nil {
+			return nil, err
+		}
+		seen[hash] = true
+		push(commit)
+	}
+
+	// Parents that can not be found, such as in a shallow clone, are where the history ends
+	parents := func(commit *object.Commit) []*object.Commit {
+		var found []*object.Commit
+		for _, hash := range commit.ParentHashes {
+			if parent, err := repository.CommitObject(hash); err == nil {
+				found = append(found, parent)
+			}
+		}
+		return found
+	}
+
+	var commits []*object.Commit
+	slop := historySlop
+	for queue.Len() > 0 {
+		commit := heap.Pop(&queue).(historyEntry).commit
+
+		// A commit that is excluded after it was walked is pushed again, so its parents are excluded as well
+		if isExcluded[commit.Hash] {
+			if !propagated[commit.Hash] {
+				propagated[commit.Hash] = true
+				for _, parent := range parents(commit) {
+					if !isExcluded[parent.Hash] {
+						seen[parent.Hash] = true
+						isExcluded[parent.Hash] = true
+						push(parent)
+					}
+				}
+			}
+		} else if !walked[commit.Hash] {
+			walked[commit.Hash] = true
+			commits = append(commits, commit)
+			for _, parent := range parents(commit) {
+				if !seen[parent.Hash] {
+					seen[parent.Hash] = true
+					push(parent)
+				}
+			}
+		}
+
+		if onlyExcluded(queue, isExcluded) {
+			slop--
+			if slop == 0 {
+				break
+			}
+		} else {
+			slop = historySlop
+		}
+	}
+
+	var history []*object.Commit
+	for _, commit := range commits {
+		if !isExcluded[commit.Hash] {
+			history = append(history, commit)
+		}
+	}
+	return topoSort(history), nil
+}
+
+// onlyExcluded will check if every commit left to walk is excluded
+func onlyExcluded(queue historyQueue, isExcluded map[plumbing.Hash]bool) bool {
+	for _, e := range queue {
+		if !isExcluded[e.commit.Hash] {
+			return false
+		}
+	}
+	return true
+}
+
+// topoSort will order commits so the children of a commit are always before it, commits that do not depend on
+// each other are kept newest first
+func topoSort(commits []*object.Commit) []*object.Commit {
+	children := make(map[plumbing.Hash]int)
+	inSet := make(map[plumbing.Hash]bool)
+	for _, commit := range commits {
+		inSet[commit.Hash] = true
+	}
+	byHash := make(map[plumbing.Hash]historyEntry)
+	for i, commit := range commits {
+		byHash[commit.Hash] = historyEntry{commit: commit, index: i}
+		for _, parent := range commit.ParentHashes {
+			if inSet[parent] {
+				children[parent]++
+			}
+		}
+	}
+
+	var ready historyQueue
+	for i, commit := range commits {
+		if children[commit.Hash] == 0 {
+			heap.Push(&ready, historyEntry{commit: commit, index: i})
+		}
+	}
+
+	sorted := make([]*object.Commit, 0, len(commits))
+	for ready.Len() > 0 {
+		commit := heap.Pop(&ready).(historyEntry).commit
+		sorted = append(sorted, commit)
+		for _, parent := range commit.ParentHashes {
+			if !inSet[parent] {
+				continue
+			}
+			children[parent]--
+			if children[parent] == 0 {
+				heap.Push(&ready, byHash[parent])
+			}
+		}
+	}
+	return sorted
+}
@@ -0,0 +1,122 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"bufio"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/gitignore"
+)
+
+// These are the names and markers used to allow known false positives from within a repo or path
+const (
+	WraithIgnoreFile      = ".wraithignore"
+	AllowMarker           = "wraith:allow"
+	ignoreSignaturePrefix = "signatureid:"
+	ignoreSecretPrefix    = "secrethash:"
+)
+
+// WraithIgnore holds the rules from a .wraithignore file. Paths use the gitignore syntax, while lines
+// starting with signatureid: or secrethash: will ignore a signature or a specific secret for the whole
+// repo or path.
+type WraithIgnore struct {
+	root         string
+	matcher      gitignore.Matcher
+	signatureIDs map[string]bool
+	secretHashes map[string]bool
+}
+
+// ParseWraithIgnore will parse the content of a .wraithignore file. The root is used to make absolute
+// paths relative before they are matched and can be empty if the paths are already relative.
+func ParseWraithIgnore(content string, root string) *WraithIgnore {
+	w := &WraithIgnore{
+		root:         root,
+		signatureIDs: make(map[string]bool),
+		secretHashes: make(map[string]bool),
+	}
+
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(strings.ToLower(line), ignoreSignaturePrefix):
+			w.signatureIDs[strings.TrimSpace(line[len(ignoreSignaturePrefix):])] = true
+		case strings.HasPrefix(strings.ToLower(line), ignoreSecretPrefix):
+			w.secretHashes[strings.ToLower(strings.TrimSpace(line[len(ignoreSecretPrefix):]))] = true
+		default:
+			patterns = append(patterns, gitignore.ParsePattern(line, nil))
+		}
+	}
+	w.matcher = gitignore.NewMatcher(patterns)
+	return w
+}
+
+// LoadWraithIgnore will read the .wraithignore file from the root of a local path. If there is no
+// file then nil is returned, which is safe to use and will not ignore anything.
+func LoadWraithIgnore(root string) *WraithIgnore {
+	data, err := ioutil.ReadFile(filepath.Join(root, WraithIgnoreFile))
+	if err != nil {
+		return nil
+	}
+	return ParseWraithIgnore(string(data), root)
+}
+
+// loadRepoWraithIgnore will read the .wraithignore file from the HEAD of a repository. This works for
+// both in memory and on disk clones.
+func loadRepoWraithIgnore(repository *git.Repository) *WraithIgnore {
+	ref, err := repository.Head()
+	if err != nil {
+		return nil
+	}
+	commit, err := repository.CommitObject(ref.Hash())
+	if err != nil {
+		return nil
+	}
+	file, err := commit.File(WraithIgnoreFile)
+	if err != nil {
+		return nil
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil
+	}
+	return ParseWraithIgnore(content, "")
+}
+
+// IsIgnoredPath will check a path against the gitignore patterns in the file
+func (w *WraithIgnore) IsIgnoredPath(path string) bool {
+	if w == nil {
+		return false
+	}
+	if w.root != "" {
+		rel, err := filepath.Rel(w.root, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return false
+		}
+		path = rel
+	}
+	return w.matcher.Match(strings.Split(filepath.ToSlash(path), "/"), false)
+}
+
+// IsIgnoredFinding will check if the signature or the secret of a finding has been ignored
+func (w *WraithIgnore) IsIgnoredFinding(finding *Finding) bool {
+	if w == nil {
+		return false
+	}
+	return w.signatureIDs[finding.SignatureID] || w.secretHashes[finding.SecretHash]
+}
+
+// hasAllowMarker will check if the line a match was found on has been marked as allowed with an
+// inline comment such as # wraith:allow
+func hasAllowMarker(lines []string, lineNumber int) bool {
+	if lineNumber < 1 || lineNumber > len(lines) {
+		return false
+	}
+	return strings.Contains(lines[lineNumber-1], AllowMarker)
+}
@@ -0,0 +1,60 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWraithIgnore(t *testing.T) {
+
+	Convey("Given a .wraithignore file", t, func() {
+		content := "# known false positives\n" +
+			"fixtures/\n" +
+			"*.lock\n" +
+			"signatureid: sig-1\n" +
+			"secrethash: " + core.HashSecret("hunter2") + "\n"
+		w := core.ParseWraithIgnore(content, "")
+
+		Convey("When a path matches a pattern", func() {
+			Convey("It should be ignored", func() {
+				So(w.IsIgnoredPath("fixtures/keys.txt"), ShouldBeTrue)
+				So(w.IsIgnoredPath("app/yarn.lock"), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a path does not match a pattern", func() {
+			Convey("It should not be ignored", func() {
+				So(w.IsIgnoredPath("config/keys.txt"), ShouldBeFalse)
+			})
+		})
+
+		Convey("When a finding has an ignored signature or secret", func() {
+			Convey("It should be ignored", func() {
+				So(w.IsIgnoredFinding(&core.Finding{SignatureID: "sig-1"}), ShouldBeTrue)
+				So(w.IsIgnoredFinding(&core.Finding{SignatureID: "sig-2", SecretHash: core.HashSecret("hunter2")}), ShouldBeTrue)
+				So(w.IsIgnoredFinding(&core.Finding{SignatureID: "sig-2", SecretHash: core.HashSecret("hunter3")}), ShouldBeFalse)
+			})
+		})
+
+		Convey("When the paths are absolute and a root is given", func() {
+			r := core.ParseWraithIgnore(content, "/tmp/project")
+
+			Convey("They should be matched relative to the root", func() {
+				So(r.IsIgnoredPath("/tmp/project/fixtures/keys.txt"), ShouldBeTrue)
+				So(r.IsIgnoredPath("/tmp/other/fixtures/keys.txt"), ShouldBeFalse)
+			})
+		})
+	})
+
+	Convey("Given no .wraithignore file", t, func() {
+		var w *core.WraithIgnore
+
+		Convey("Nothing should be ignored", func() {
+			So(w.IsIgnoredPath("fixtures/keys.txt"), ShouldBeFalse)
+			So(w.IsIgnoredFinding(&core.Finding{SignatureID: "sig-1"}), ShouldBeFalse)
+		})
+	})
+}
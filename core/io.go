@@ -56,7 +56,7 @@ func SetHomeDir(h string, sess *Session) string {
 		home, err := homedir.Dir()
 		if err != nil {
 			sess.Out.Error(err.Error())
-			os.Exit(2)
+			os.Exit(ExitCodeError)
 		}
 
 		h = strings.Replace(h, "$HOME", home, -1)
@@ -66,7 +66,7 @@ func SetHomeDir(h string, sess *Session) string {
 		home, err := homedir.Dir()
 		if err != nil {
 			sess.Out.Error(err.Error())
-			os.Exit(2)
+			os.Exit(ExitCodeError)
 		}
 		h = strings.Replace(h, "~", home, -1)
 	}
@@ -75,28 +75,61 @@ func SetHomeDir(h string, sess *Session) string {
 
 // realTimeOutput will print out the current finding to stdout if all conditions are met
 func realTimeOutput(finding *Finding, sess *Session) {
-	if !sess.Silent && !sess.CSVOutput && !sess.JSONOutput {
+	if !sess.Silent && !sess.CSVOutput && !sess.JSONOutput && !sess.SARIFOutput {
 
 		sess.Out.Warn(" %s\n", strings.ToUpper(finding.Description))
 		sess.Out.Info("  SignatureID..........: %s\n", finding.SignatureID)
 		sess.Out.Info("  Repo.................: %s\n", finding.RepositoryName)
 		sess.Out.Info("  File Path............: %s\n", finding.FilePath)
 		sess.Out.Info("  Line Number..........: %s\n", finding.LineNumber)
+		if finding.ColumnNumber > 0 {
+			sess.Out.Info("  Column Number........: %d\n", finding.ColumnNumber)
+		}
 		sess.Out.Info("  Message..............: %s\n", TruncateString(finding.CommitMessage, 100))
 		sess.Out.Info("  Commit Hash..........: %s\n", TruncateString(finding.CommitHash, 100))
+		if finding.PullRequest != "" {
+			sess.Out.Info("  Pull Request.........: #%s %s\n", finding.PullRequest, finding.PullRequestURL)
+		}
 		sess.Out.Info("  Author...............: %s\n", finding.CommitAuthor)
-		sess.Out.Info("  SecretID.............: %v\n", finding.SecretID)
+		sess.Out.Info("  Fingerprint..........: %v\n", finding.Fingerprint)
+		if finding.SecretID != "" {
+			sess.Out.Info("  SecretID.............: %v\n", finding.SecretID)
+		}
 		sess.Out.Info("  Wraith Version.......: %s\n", finding.WraithVersion)
 		sess.Out.Info("  Signatures Version...: %v\n", finding.signatureVersion)
 		if len(finding.Content) > 0 {
 			issues := "\n\t" + finding.Content
 			sess.Out.Info("  Issues..........: %s\n", issues)
 		}
+		if sess.HideSecrets || sess.RedactSecrets {
+			sess.Out.Info("  Secret Hash..........: %s\n", finding.SecretHash)
+		}
+		if len(finding.Line) > 0 {
+			sess.Out.Info("  Line.................: %s\n", strings.ReplaceAll(finding.Line, "\n", "\n\t"))
+		}
+		if len(finding.ContextBefore) > 0 || len(finding.ContextAfter) > 0 {
+			sess.Out.Info("  Context..............: %s\n", formatContext(finding))
+		}
 
 		sess.Out.Info(" ------------------------------------------------\n\n")
 	}
 }
 
+// formatContext will put the line of a finding between the lines of context around it, marking the line itself
+func formatContext(finding *Finding) string {
+	var b strings.Builder
+	for _, line := range finding.ContextBefore {
+		b.WriteString("\n\t  " + line)
+	}
+	for _, line := range strings.Split(finding.Line, "\n") {
+		b.WriteString("\n\t> " + line)
+	}
+	for _, line := range finding.ContextAfter {
+		b.WriteString("\n\t  " + line)
+	}
+	return b.String()
+}
+
 // IsMaxFileSize will determine if the file size is under the max limit set by maxFileSize
 func IsMaxFileSize(filename string, sess *Session) (bool, string) {
 
@@ -0,0 +1,157 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"strings"
+)
+
+// KeywordFilter finds the keywords of the signatures in some content with a single Aho-Corasick pass, so the regex
+// of a signature with keywords only has to be run when at least one of them is in the content. Keywords are
+// matched without regard to the case of ASCII letters.
+type KeywordFilter struct {
+	keywords []string
+	next     []map[byte]int // The transitions from each state of the trie
+	fail     []int          // The state to fall back to when there is no transition
+	out      [][]int        // The keywords that end at each state, including those of the fallback states
+}
+
+// NewKeywordFilter will build a filter from the keywords of the signatures, nil is returned if none of them have any
+func NewKeywordFilter(signatures []Signature) *KeywordFilter {
+	f := &KeywordFilter{next: []map[byte]int{{}}, fail: []int{0}, out: [][]int{nil}}
+	seen := make(map[string]bool)
+	for _, signature := range signatures {
+		for _, k := range signatureKeywords(signature) {
+			if k != "" && !seen[k] {
+				seen[k] = true
+				f.add(k)
+			}
+		}
+	}
+	if len(f.keywords) == 0 {
+		return nil
+	}
+	f.build()
+	return f
+}
+
+// signatureKeywords will get the keywords of a content signature, other signatures are never filtered
+func signatureKeywords(signature Signature) []string {
+	if ps, ok := signature.(PatternSignature); ok && ps.part == PartContent {
+		return ps.keywords
+	}
+	return nil
+}
+
+// add will add a keyword to the trie
+func (f *KeywordFilter) add(keyword string) {
+	state := 0
+	for i := 0; i < len(keyword); i++ {
+		next, ok := f.next[state][keyword[i]]
+		if !ok {
+			next = len(f.next)
+			f.next = append(f.next, map[byte]int{})
+			f.fail = append(f.fail, 0)
+			f.out = append(f.out, nil)
+			f.next[state][keyword[i]] = next
+		}
+		state = next
+	}
+	f.out[state] = append(f.out[state], len(f.keywords))
+	f.keywords = append(f.keywords, keyword)
+}
+
+// build will set the fallback of each state by walking the trie breadth first
+func (f *KeywordFilter) build() {
+	var queue []int
+	for _, next := range f.next[0] {
+		queue = append(queue, next)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for b, next := range f.next[state] {
+			fail := f.fail[state]
+			for fail != 0 && !f.hasNext(fail, b) {
+				fail = f.fail[fail]
+			}
+			if n, ok := f.next[fail][b]; ok && n != next {
+				fail = n
+			}
+			f.fail[next] = fail
+			f.out[next] = append(f.out[next], f.out[fail]...)
+			queue = append(queue, next)
+		}
+	}
+}
+
+// hasNext will check if there is a transition from a state for a byte
+func (f *KeywordFilter) hasNext(state int, b byte) bool {
+	_, ok := f.next[state][b]
+	return ok
+}
+
+// Find will return the keywords that are in the content
+func (f *KeywordFilter) Find(content string) map[string]bool {
+	found := make(map[string]bool)
+	state := 0
+	for i := 0; i < len(content); i++ {
+		b := lowerASCII(content[i])
+		for state != 0 && !f.hasNext(state, b) {
+			state = f.fail[state]
+		}
+		if next, ok := f.next[state][b]; ok {
+			state = next
+		}
+		for _, k := range f.out[state] {
+			found[f.keywords[k]] = true
+		}
+		if len(found) == len(f.keywords) {
+			break
+		}
+	}
+	return found
+}
+
+// MayMatch will check if a signature needs to be run against content with the keywords that were found. Signatures
+// without keywords always need to be run.
+func (f *KeywordFilter) MayMatch(signature Signature, found map[string]bool) bool {
+	if f == nil || found == nil {
+		return true
+	}
+	keywords := signatureKeywords(signature)
+	if len(keywords) == 0 {
+		return true
+	}
+	for _, k := range keywords {
+		if found[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// lowerASCII will lower the case of an ASCII letter, keywords are compared a byte at a time
+func lowerASCII(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+// normalizeKeywords will lower the case of the ASCII letters of the keywords of a signature, the same as is done
+// to the content, so they match it without regard to case. Other letters have to be in the same case as the content.
+func normalizeKeywords(keywords []string) []string {
+	var normalized []string
+	for _, k := range keywords {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		b := []byte(k)
+		for i := range b {
+			b[i] = lowerASCII(b[i])
+		}
+		normalized = append(normalized, string(b))
+	}
+	return normalized
+}
@@ -0,0 +1,84 @@
+package core_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const keywordSignatures = `Meta:
+  Version: "0.0.1"
+PatternSignatures:
+  - description: "AWS Access Key ID"
+    enable: 1
+    match: "AKIA[0-9A-Z]{16}"
+    confidence-level: 3
+    part: "partcontent"
+    signatureid: "aws-key"
+    keywords: [AKIA]
+  - description: "Overlapping keywords"
+    enable: 1
+    match: "h[a-z]+"
+    confidence-level: 3
+    part: "partcontent"
+    signatureid: "overlapping"
+    keywords: [he, she, hers, his]
+  - description: "Password"
+    enable: 1
+    match: "password"
+    confidence-level: 3
+    part: "partcontent"
+    signatureid: "password"
+  - description: "Key with a non-ASCII keyword"
+    enable: 1
+    match: "ÜBERKEY=[0-9a-f]+"
+    confidence-level: 3
+    part: "partcontent"
+    signatureid: "uberkey"
+    keywords: [ÜBERKEY]
+`
+
+func TestKeywordFilter(t *testing.T) {
+
+	Convey("Given signatures with keywords", t, func() {
+		dir, _ := ioutil.TempDir("", "wraith")
+		defer os.RemoveAll(dir)
+
+		sigPath := filepath.Join(dir, "signatures.yaml")
+		_ = ioutil.WriteFile(sigPath, []byte(keywordSignatures), 0644)
+		signatures := core.LoadSignatures(sigPath, 0, &core.Session{})
+		filter := core.NewKeywordFilter(signatures)
+
+		Convey("When keywords overlap in the content", func() {
+			found := filter.Find("ushers")
+
+			Convey("Every keyword in the content should be found", func() {
+				So(found, ShouldResemble, map[string]bool{"he": true, "she": true, "hers": true})
+			})
+		})
+
+		Convey("When the content has a keyword in a different case", func() {
+			found := filter.Find("key = akiaIOSFODNN7EXAMPLE")
+
+			Convey("Only the signatures with a keyword in the content or without keywords may match", func() {
+				So(filter.MayMatch(signatures[0], found), ShouldBeTrue)
+				So(filter.MayMatch(signatures[1], found), ShouldBeFalse)
+				So(filter.MayMatch(signatures[2], found), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a keyword has letters that are not ASCII", func() {
+			found := filter.Find("ÜberKey=0123abcd")
+
+			Convey("The keyword should be found without regard to the case of its ASCII letters", func() {
+				So(found, ShouldContainKey, "Überkey")
+				So(filter.MayMatch(signatures[3], found), ShouldBeTrue)
+			})
+		})
+	})
+}
@@ -0,0 +1,193 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// secretKey identifies a distinct secret within a repository, the same secret in another file is tracked on its own
+type secretKey struct {
+	path        string
+	signatureID string
+	secretHash  string
+}
+
+// commitFile is a file at a given commit
+type commitFile struct {
+	commit plumbing.Hash
+	path   string
+}
+
+// commitFiles finds the blob of a file at a commit, each file is only looked up once for all of the secrets in a
+// repository. Only the blobs are kept and not the trees they were found in, so the memory used does not grow with the
+// size of the trees in the history.
+type commitFiles map[commitFile]plumbing.Hash
+
+// blob will get the blob of a file at a commit, with a false if the commit does not have the file
+func (c commitFiles) blob(commit *object.Commit, path string) (plumbing.Hash, bool) {
+	key := commitFile{commit: commit.Hash, path: path}
+	if hash, ok := c[key]; ok {
+		return hash, !hash.IsZero()
+	}
+
+	var hash plumbing.Hash
+	if tree, err := commit.Tree(); err == nil {
+		if entry, err := tree.FindEntry(path); err == nil {
+			hash = entry.Hash
+		}
+	}
+	c[key] = hash
+	return hash, !hash.IsZero()
+}
+
+// secretPresence checks if a secret is in a file at a given commit. Files that did not change between commits
+// have the same blob, so the result for each blob is only worked out once.
+type secretPresence struct {
+	clone  *git.Repository
+	files  commitFiles
+	path   string
+	secret string
+	blobs  map[plumbing.Hash]bool
+}
+
+// in will check if the secret is in the file at a commit, a commit without the file does not have the secret
+func (p *secretPresence) in(commit *object.Commit) bool {
+	hash, ok := p.files.blob(commit, p.path)
+	if !ok {
+		return false
+	}
+	if present, ok := p.blobs[hash]; ok {
+		return present
+	}
+
+	present := false
+	if blob, err := p.clone.BlobObject(hash); err == nil {
+		if reader, err := blob.Reader(); err == nil {
+			contents, err := ioutil.ReadAll(reader)
+			_ = reader.Close()
+			present = err == nil && strings.Contains(string(contents), p.secret)
+		}
+	}
+	p.blobs[hash] = present
+	return present
+}
+
+// setSecretLifecycle will work out when each distinct secret found in a repository was introduced, the last commit
+// it was present in, the commit that removed it and if it is still present at HEAD. The same secret can be found
+// in more than one commit, such as when it is moved around within a file, so all of its findings share these.
+// Commits are ordered by where they are in the history, which has the children of a commit before it, as the
+// commit times can not be trusted after a rebase or with clocks that are out of sync. When the history is only part
+// of the history of the repository, such as the commits since the last scan, only PresentAtHead is set.
+func setSecretLifecycle(sess *Session, clone *git.Repository, history []*object.Commit, findings []*Finding, partial bool) {
+	if len(findings) == 0 {
+		return
+	}
+
+	var head *object.Commit
+	if ref, err := clone.Head(); err == nil {
+		head, _ = clone.CommitObject(ref.Hash())
+	}
+
+	// A commit further along the history is older
+	position := make(map[string]int)
+	for i, c := range history {
+		position[c.Hash.String()] = i
+	}
+	files := make(commitFiles)
+
+	groups := make(map[secretKey][]*Finding)
+	var keys []secretKey
+	for _, f := range findings {
+		key := secretKey{path: f.FilePath, signatureID: f.SignatureID, secretHash: f.SecretHash}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], f)
+	}
+
+	for _, key := range keys {
+		group := groups[key]
+		if group[0].secret == "" {
+			continue
+		}
+		presence := &secretPresence{
+			clone:  clone,
+			files:  files,
+			path:   key.path,
+			secret: group[0].secret,
+			blobs:  make(map[plumbing.Hash]bool),
+		}
+
+		// The oldest commit any of the findings were found in is the one that introduced the secret
+		introduced := -1
+		for _, f := range group {
+			for _, hash := range append([]string{f.CommitHash}, f.Commits...) {
+				if i, ok := position[hash]; ok && i > introduced {
+					introduced = i
+				}
+			}
+		}
+
+		atHead := head != nil && presence.in(head)
+		if partial {
+			sess.Lock()
+			for _, f := range group {
+				f.PresentAtHead = atHead
+			}
+			sess.Unlock()
+			continue
+		}
+
+		var lastPresent, removed *object.Commit
+		if atHead {
+			lastPresent = head
+		} else {
+			for _, c := range history {
+				if presence.in(c) {
+					lastPresent = c
+					break
+				}
+			}
+
+			// The secret was removed by the oldest child of the last commit it was present in that does not have it
+			if lastPresent != nil {
+				for i := position[lastPresent.Hash.String()] - 1; i >= 0 && removed == nil; i-- {
+					for _, parent := range history[i].ParentHashes {
+						if parent == lastPresent.Hash && !presence.in(history[i]) {
+							removed = history[i]
+							break
+						}
+					}
+				}
+			}
+		}
+
+		sess.Lock()
+		for _, f := range group {
+			if introduced >= 0 {
+				f.IntroducedCommit = history[introduced].Hash.String()
+			}
+			if lastPresent != nil {
+				f.LastPresentCommit = lastPresent.Hash.String()
+			}
+			if removed != nil {
+				f.RemovedCommit = removed.Hash.String()
+			}
+			f.PresentAtHead = atHead
+		}
+		sess.Unlock()
+	}
+}
+
+// forgetSecrets will clear the secrets of findings once they are no longer needed, they are not kept around in case
+// they are meant to be hidden
+func forgetSecrets(findings []*Finding) {
+	for _, f := range findings {
+		f.secret = ""
+	}
+}
@@ -58,7 +58,8 @@ func Search(ctx context.Context, root string, skippablePath []string, sess *Sess
 }
 
 // DoFileScan with create a match object and then test for various criteria necessary in order to determine if it should be scanned. This includes if it should be skipped due to a default or user supplied extension, if it matches a test regex, or is in a protected directory or is itself protected. This will only run when doing scanLocalPath.
-func DoFileScan(filename string, sess *Session) {
+// The ignore rules come from the .wraithignore of the path being scanned and may be nil.
+func DoFileScan(filename string, ignore *WraithIgnore, sess *Session) {
 
 	// Set default values for all pre-requisites for a file scan
 	likelyTestFile := false
@@ -73,6 +74,12 @@ func DoFileScan(filename string, sess *Session) {
 		return
 	}
 
+	if ignore.IsIgnoredPath(filename) {
+		sess.Out.Debug("%s is in %s and being ignored\n", filename, WraithIgnoreFile)
+		sess.Stats.IncrementFilesIgnored()
+		return
+	}
+
 	// If we are not scanning tests then drop all files that match common test file patterns
 	// If we do not want to scan any test files or paths we check for them and then exclude them if they are found
 	// The default is to not scan test files or common test paths
@@ -125,11 +132,6 @@ func DoFileScan(filename string, sess *Session) {
 
 			content = cleanK[1]
 
-			// destroy the secret if the flag is set
-			if sess.HideSecrets {
-				content = ""
-			}
-
 			if bMatched {
 				newFinding := &Finding{
 					FilePath:         filename,
@@ -142,15 +144,26 @@ func DoFileScan(filename string, sess *Session) {
 					CommitHash:       ``,
 					CommitMessage:    ``,
 					CommitAuthor:     ``,
-					LineNumber:       strconv.Itoa(v),
-					SecretID:         generateID(),
+					LineNumber:       strconv.Itoa(v.StartLine),
+					ColumnNumber:     v.StartColumn,
+					EndLineNumber:    v.EndLine,
+					EndColumnNumber:  v.EndColumn,
+					Line:             v.Line,
+					ContextBefore:    v.Before,
+					ContextAfter:     v.After,
 					WraithVersion:    version.AppVersion(),
 					signatureVersion: sess.SignatureVersion,
 				}
 
 				// Add a new finding and increment the total
 				newFinding.Initialize(sess)
-				sess.AddFinding(newFinding)
+				if ignore.IsIgnoredFinding(newFinding) {
+					sess.Stats.IncrementFindingsSuppressed()
+					continue
+				}
+				if !sess.AddFinding(newFinding) {
+					continue
+				}
 
 				// print the current finding to stdout
 				realTimeOutput(newFinding, sess)
@@ -169,8 +182,12 @@ func ScanDir(path string, sess *Session) {
 	files, err1 := Search(ctx, path, sess.SkippablePath, sess)
 	if err1 != nil {
 		sess.Out.Error("There is an error scanning %s: %s\n", path, err1.Error())
+		sess.Stats.IncrementScanFailures()
 	}
 
+	// Load any paths, signatures or secrets that have been marked as known false positives
+	ignore := LoadWraithIgnore(path)
+
 	maxThreads := 100
 	sem := make(chan struct{}, maxThreads)
 
@@ -184,7 +201,7 @@ func ScanDir(path string, sess *Session) {
 			defer wg.Done()
 
 			// scan the specific file if it is found to be a valid candidate
-			DoFileScan(p, sess)
+			DoFileScan(p, ignore, sess)
 			<-sem
 		}()
 	}
@@ -196,11 +213,11 @@ func ScanDir(path string, sess *Session) {
 func CheckArgs(sFile []string, sDir []string, sess *Session) {
 	if sFile != nil && sDir != nil {
 		sess.Out.Error("You cannot set both scan-file and scan-dir at the same time\n")
-		os.Exit(1)
+		os.Exit(ExitCodeError)
 	}
 
 	if sFile == nil && sDir == nil {
 		sess.Out.Error("You must set either a path or file to scan\n")
-		os.Exit(1)
+		os.Exit(ExitCodeError)
 	}
 }
@@ -25,6 +25,12 @@ func cloneLocal(cloneConfig *CloneConfiguration) (*git.Repository, string, error
 		Tags:          git.NoTags,
 	}
 
+	// Every branch and tag is fetched when more than the default branch is being scanned
+	if *cloneConfig.AllRefs {
+		cloneOptions.SingleBranch = false
+		cloneOptions.Tags = git.AllTags
+	}
+
 	var repository *git.Repository
 	var err error
 	var dir string
@@ -58,7 +64,7 @@ func GatherLocalRepositories(sess *Session) {
 
 		if !PathExists(pth, sess) {
 			sess.Out.Error("\n[*] <%s> does not exist! Quitting.\n", pth)
-			os.Exit(1)
+			os.Exit(ExitCodeError)
 		}
 
 		// Gather all paths in the tree
@@ -87,6 +93,7 @@ func GatherLocalRepositories(sess *Session) {
 					ref, err3 := openRepo.Head()
 					if err3 != nil {
 						sess.Out.Error("Failed to open the repo HEAD: %s\n", err3.Error())
+						sess.Stats.IncrementScanFailures()
 						return nil
 					}
 
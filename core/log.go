@@ -18,6 +18,13 @@ const (
 	DEBUG     = 0
 )
 
+// These are the exit codes returned by wraith so the result of a scan can be acted on without parsing the output
+const (
+	ExitCodeNoFindings = 0 // The scan finished without any findings that meet the fail-on thresholds
+	ExitCodeFindings   = 1 // The scan finished with findings that meet the fail-on thresholds
+	ExitCodeError      = 2 // The scan had errors or was unable to run
+)
+
 // LogColors sets the color for each type of logging output
 var LogColors = map[int]*color.Color{
 	FATAL:     color.New(color.FgRed).Add(color.Bold),
@@ -62,7 +69,7 @@ func (l *Logger) Log(level int, format string, args ...interface{}) {
 	}
 
 	if level == FATAL {
-		os.Exit(1)
+		os.Exit(ExitCodeError)
 	}
 }
 
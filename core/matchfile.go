@@ -1,12 +1,13 @@
 package core
 
 import (
-	//"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"path/filepath"
 	//"strconv"
 	"strings"
 	//"fmt"
 	//"github.com/N0MoreSecr3ts/wraith/version"
+
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
 // MatchFile holds the various parts of a file that will be matched using either regex's or simple pattern matches.
@@ -14,6 +15,38 @@ type MatchFile struct {
 	Path      string
 	Filename  string
 	Extension string
+
+	additions       []ChangeAddition // The lines added by a change, loaded once and shared by every signature
+	additionsLoaded bool
+	content         string // The content of a file that has already been read, such as a staged blob
+	contentLoaded   bool
+}
+
+// setContent will set the content of a file that has already been read, so content signatures match it rather
+// than a change or the file on disk
+func (f *MatchFile) setContent(content string) {
+	f.content = content
+	f.contentLoaded = true
+}
+
+// loadAdditions will get the lines added by a change so they do not have to be worked out again for each signature
+func (f *MatchFile) loadAdditions(change *object.Change) error {
+	additions, err := GetChangeAdditions(change)
+	if err != nil {
+		return err
+	}
+	f.additions = additions
+	f.additionsLoaded = true
+	return nil
+}
+
+// addedContent will join the lines added by a change, these must have been loaded first
+func (f *MatchFile) addedContent() string {
+	var b strings.Builder
+	for _, addition := range f.additions {
+		b.WriteString(addition.Content)
+	}
+	return b.String()
 }
 
 // newMatchFile will generate a match object by dissecting a filename
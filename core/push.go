@@ -0,0 +1,319 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/src-d/go-billy.v4/helper/mount"
+	"gopkg.in/src-d/go-billy.v4/helper/polyfill"
+	"gopkg.in/src-d/go-billy.v4/osfs"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/cache"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+	"gopkg.in/src-d/go-git.v4/storage/filesystem"
+	"gopkg.in/src-d/go-git.v4/storage/filesystem/dotgit"
+)
+
+// ZeroCommitID is the revision git sends to a hook when a ref is being created or deleted
+const ZeroCommitID = "0000000000000000000000000000000000000000"
+
+// PushUpdate is a single ref update as git passes it to a pre-receive hook on stdin
+type PushUpdate struct {
+	OldRev  string
+	NewRev  string
+	RefName string
+}
+
+// PushRejection is a finding along with the ref that was being pushed when it was found
+type PushRejection struct {
+	RefName string
+	Finding *Finding
+}
+
+// ParsePushUpdates will read the "oldrev newrev refname" lines that git passes to a pre-receive hook
+func ParsePushUpdates(r io.Reader) ([]PushUpdate, error) {
+	var updates []PushUpdate
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("expected 'oldrev newrev refname' but got %q", line)
+		}
+		updates = append(updates, PushUpdate{OldRev: fields[0], NewRev: fields[1], RefName: fields[2]})
+	}
+	return updates, scanner.Err()
+}
+
+// quarantineStorer will look for objects in the quarantine directory of a push before falling back to
+// the repository. Since git 2.11 the objects of a push are not moved into the repository until the
+// pre-receive hook has accepted them.
+type quarantineStorer struct {
+	*filesystem.Storage
+	incoming *filesystem.ObjectStorage
+}
+
+// EncodedObject will get an object from the quarantine directory or the repository
+func (s *quarantineStorer) EncodedObject(t plumbing.ObjectType, h plumbing.Hash) (plumbing.EncodedObject, error) {
+	obj, err := s.incoming.EncodedObject(t, h)
+	if err == plumbing.ErrObjectNotFound {
+		return s.Storage.EncodedObject(t, h)
+	}
+	return obj, err
+}
+
+// HasEncodedObject will check for an object in the quarantine directory or the repository
+func (s *quarantineStorer) HasEncodedObject(h plumbing.Hash) error {
+	if err := s.incoming.HasEncodedObject(h); err == nil {
+		return nil
+	}
+	return s.Storage.HasEncodedObject(h)
+}
+
+// openPushRepository will open the bare repository a hook is running in. If git has quarantined the
+// objects of the push, they are read from there as well.
+func openPushRepository(repoPath string) (*git.Repository, error) {
+	repository, err := git.PlainOpen(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	quarantinePath := os.Getenv("GIT_QUARANTINE_PATH")
+	if quarantinePath == "" {
+		return repository, nil
+	}
+
+	fs, ok := repository.Storer.(*filesystem.Storage)
+	if !ok {
+		return repository, nil
+	}
+
+	// The quarantine directory is laid out like an objects directory so it is mounted where dotgit expects one
+	incomingFS := polyfill.New(mount.New(fs.Filesystem(), "objects", osfs.New(quarantinePath)))
+	incoming := filesystem.NewObjectStorage(dotgit.New(incomingFS), cache.NewObjectLRUDefault())
+
+	return git.Open(&quarantineStorer{Storage: fs, incoming: incoming}, nil)
+}
+
+// getExistingCommits will collect every commit that is reachable from a ref in the repository. These have
+// already been accepted so there is no need to scan them again, the same as 'git rev-list --not --all'.
+func getExistingCommits(repository *git.Repository) (map[plumbing.Hash]bool, error) {
+	existing := make(map[plumbing.Hash]bool)
+
+	refs, err := repository.References()
+	if err != nil {
+		return nil, err
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		// Tags may point to a tag object rather than a commit, anything else that is not a commit is skipped
+		hash := ref.Hash()
+		if tag, err := repository.TagObject(hash); err == nil {
+			hash = tag.Target
+		}
+		commit, err := repository.CommitObject(hash)
+		if err != nil || existing[commit.Hash] {
+			return nil
+		}
+		return object.NewCommitPreorderIter(commit, existing, nil).ForEach(func(c *object.Commit) error {
+			existing[c.Hash] = true
+			return nil
+		})
+	})
+	return existing, err
+}
+
+// GetPushRangeCommits will get the commits that a ref update adds to the repository. Commits that are in
+// the seen map are skipped, which is used to exclude existing commits and those already returned for another
+// ref in the same push. A deleted ref has no new commits.
+func GetPushRangeCommits(repository *git.Repository, update PushUpdate, seen map[plumbing.Hash]bool) ([]*object.Commit, error) {
+	if update.NewRev == ZeroCommitID {
+		return nil, nil
+	}
+
+	// A tag may be pushed as a tag object, in which case the tagged commit is the one to walk from
+	hash := plumbing.NewHash(update.NewRev)
+	if tag, err := repository.TagObject(hash); err == nil {
+		hash = tag.Target
+	}
+	newCommit, err := repository.CommitObject(hash)
+	if err == plumbing.ErrObjectNotFound {
+		// The ref points to something other than a commit such as a tree or a blob
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var commits []*object.Commit
+	err = object.NewCommitPreorderIter(newCommit, seen, nil).ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = true
+		commits = append(commits, c)
+		return nil
+	})
+	if err != nil && err != storer.ErrStop {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// ScanPushRange will scan the commits that a push adds to a bare repository and return a rejection for every
+// finding. This is meant to be run from a pre-receive hook, so nothing is cloned and only the objects in the
+// repository and the quarantine directory of the push are read.
+func ScanPushRange(repoPath string, updates []PushUpdate, sess *Session) []PushRejection {
+	sess.Stats.Status = StatusAnalyzing
+
+	repository, err := openPushRepository(repoPath)
+	if err != nil {
+		sess.Out.Fatal("Unable to open the git repo at %s: %s\n", repoPath, err)
+	}
+
+	root, err := filepath.Abs(repoPath)
+	if err != nil {
+		root = repoPath
+	}
+	repoOwner, repoName := GetRepositoryIdentity(repository, root)
+
+	seen, err := getExistingCommits(repository)
+	if err != nil {
+		sess.Out.Fatal("Unable to read the refs of %s: %s\n", repoPath, err)
+	}
+
+	// Load any paths, signatures or secrets the repo has marked as known false positives
+	ignore := loadRepoWraithIgnore(repository)
+
+	var rejections []PushRejection
+	for _, update := range updates {
+		commits, err := GetPushRangeCommits(repository, update, seen)
+		if err != nil {
+			sess.Out.Fatal("Unable to read the commits pushed to %s: %s\n", update.RefName, err)
+		}
+		sess.Out.Debug("%d new %s pushed to %s\n", len(commits), Pluralize(len(commits), "commit", "commits"), update.RefName)
+
+		sess.Stats.CommitsTotal = sess.Stats.CommitsTotal + len(commits)
+
+		for _, commit := range commits {
+			sess.Stats.IncrementCommitsScanned()
+			dirtyCommit := false
+
+			changes, err := GetChanges(commit, repository)
+			if err != nil {
+				sess.Out.Fatal("Unable to read the changes in %s: %s\n", commit.Hash, err)
+			}
+
+			for _, change := range changes {
+				sess.Stats.IncrementFilesTotal()
+
+				changeAction := GetChangeAction(change)
+				fPath := GetChangePath(change)
+
+				if !sess.ScanTests && isTestFileOrPath(fPath) {
+					sess.Stats.IncrementFilesIgnored()
+					sess.Out.Debug("%s is a test file and being ignored\n", fPath)
+					continue
+				}
+
+				// There is no working tree so the size comes from the blob that is being pushed
+				if change.To.Tree != nil {
+					if file, err := change.To.Tree.TreeEntryFile(&change.To.TreeEntry); err == nil && file.Size > sess.MaxFileSize*1024*1024 {
+						sess.Stats.IncrementFilesIgnored()
+						sess.Out.Debug("%s is too large\n", fPath)
+						continue
+					}
+				}
+
+				matchFile := newMatchFile(fPath)
+				if matchFile.isSkippable(sess) || ignore.IsIgnoredPath(fPath) {
+					sess.Stats.IncrementFilesIgnored()
+					sess.Out.Debug("%s is skippable and being ignored\n", fPath)
+					continue
+				}
+
+				sess.Stats.IncrementFilesScanned()
+				dirtyFile := false
+
+				// Only the signatures with keywords in the lines added by the change may match
+				var keywords map[string]bool
+				if err := matchFile.loadAdditions(change); err == nil && SignatureKeywords != nil {
+					keywords = SignatureKeywords.Find(matchFile.addedContent())
+				}
+
+				for _, signature := range Signatures {
+					if !SignatureKeywords.MayMatch(signature, keywords) {
+						continue
+					}
+
+					// Content signatures are run against the lines added by the change, there is no working tree
+					bMatched, matchMap := signature.ExtractMatch(matchFile, sess, change)
+					if !bMatched {
+						continue
+					}
+					dirtyFile = true
+					dirtyCommit = true
+
+					for k, v := range matchMap {
+						finding := newFinding(sess, signature, k, v, fPath, changeAction, commit)
+						finding.RepositoryName = repoName
+						finding.RepositoryOwner = repoOwner
+						finding.Initialize(sess)
+
+						if ignore.IsIgnoredFinding(finding) {
+							sess.Stats.IncrementFindingsSuppressed()
+							continue
+						}
+						if !sess.AddFinding(finding) {
+							continue
+						}
+						rejections = append(rejections, PushRejection{RefName: update.RefName, Finding: finding})
+					}
+				}
+
+				if dirtyFile {
+					sess.Stats.IncrementFilesDirty()
+				}
+			}
+
+			if dirtyCommit {
+				sess.Stats.IncrementCommitsDirty()
+			}
+		}
+	}
+	return rejections
+}
+
+// PushRejectionOutput will print a short report of every finding that caused a push to be rejected. This is
+// what the person pushing will see, so each finding is kept to a few lines.
+func PushRejectionOutput(rejections []PushRejection, sess *Session) {
+	if len(rejections) == 0 {
+		return
+	}
+
+	sess.Out.Warn("Push rejected, wraith found %d %s in the pushed commits\n\n", len(rejections), Pluralize(len(rejections), "secret", "secrets"))
+	for _, r := range rejections {
+		f := r.Finding
+		sess.Out.Warn(" %s\n", strings.ToUpper(f.Description))
+		sess.Out.Info("  Ref..................: %s\n", r.RefName)
+		sess.Out.Info("  Commit Hash..........: %s\n", f.CommitHash)
+		sess.Out.Info("  Author...............: %s\n", f.CommitAuthor)
+		sess.Out.Info("  File Path............: %s:%s\n", f.FilePath, f.LineNumber)
+		sess.Out.Info("  SignatureID..........: %s\n", f.SignatureID)
+		sess.Out.Info("  Fingerprint..........: %s\n", f.Fingerprint)
+		if len(f.Content) > 0 {
+			sess.Out.Info("  Secret...............: %s\n", f.Content)
+		}
+		sess.Out.Info(" ------------------------------------------------\n\n")
+	}
+	sess.Out.Important("Remove the secrets from the commits, or mark known false positives in %s or with an inline %s comment, and push again.\n", WraithIgnoreFile, AllowMarker)
+}
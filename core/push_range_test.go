@@ -0,0 +1,149 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-billy.v4/osfs"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/cache"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/plumbing/revlist"
+	"gopkg.in/src-d/go-git.v4/plumbing/storer"
+	"gopkg.in/src-d/go-git.v4/storage/filesystem"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+// copyObjects will copy the objects reachable from objs, and not from ignore, from one storer to another
+func copyObjects(from, to storer.EncodedObjectStorer, objs, ignore []plumbing.Hash) {
+	hashes, err := revlist.Objects(from, objs, ignore)
+	So(err, ShouldBeNil)
+	for _, hash := range hashes {
+		obj, err := from.EncodedObject(plumbing.AnyObject, hash)
+		So(err, ShouldBeNil)
+		_, err = to.SetEncodedObject(obj)
+		So(err, ShouldBeNil)
+	}
+}
+
+func TestPushRange(t *testing.T) {
+
+	Convey("Given a bare repo and a push whose objects are in a quarantine directory", t, func() {
+		dir, _ := ioutil.TempDir("", "wraith")
+		defer os.RemoveAll(dir)
+
+		// The history is made in memory, then split between the repo and the quarantine directory
+		src, _ := git.Init(memory.NewStorage(), memfs.New())
+		wt, _ := src.Worktree()
+		when := time.Now().Add(-time.Hour)
+		base := commitLifecycleFile(wt, "README.md", "readme\n", when)
+		pushed := commitLifecycleFile(wt, "config.ini", "key = "+lifecycleSecret+"\n", when.Add(time.Minute))
+		branched := commitLifecycleFile(wt, "other.txt", "other\n", when.Add(2*time.Minute))
+		tagRef, _ := src.CreateTag("v1.0.0", branched, &git.CreateTagOptions{
+			Tagger:  &object.Signature{Name: "wraith", When: when.Add(3 * time.Minute)},
+			Message: "v1.0.0",
+		})
+		tag := tagRef.Hash()
+
+		repoPath := filepath.Join(dir, "repo.git")
+		bare, _ := git.PlainInit(repoPath, true)
+		copyObjects(src.Storer, bare.Storer, []plumbing.Hash{base}, nil)
+		_ = bare.Storer.SetReference(plumbing.NewHashReference("refs/heads/master", base))
+
+		quarantine := filesystem.NewStorage(osfs.New(filepath.Join(dir, "incoming")), cache.NewObjectLRUDefault())
+		copyObjects(src.Storer, quarantine, []plumbing.Hash{tag}, []plumbing.Hash{base})
+		quarantinePath := filepath.Join(dir, "incoming", "objects")
+
+		Convey("When the repo is opened without the quarantine directory", func() {
+			repo, err := openPushRepository(repoPath)
+
+			Convey("The pushed commits should not be found", func() {
+				So(err, ShouldBeNil)
+				_, err = repo.CommitObject(pushed)
+				So(err, ShouldEqual, plumbing.ErrObjectNotFound)
+			})
+		})
+
+		Convey("When the repo is opened with the quarantine directory", func() {
+			_ = os.Setenv("GIT_QUARANTINE_PATH", quarantinePath)
+			defer os.Unsetenv("GIT_QUARANTINE_PATH")
+			repo, err := openPushRepository(repoPath)
+			So(err, ShouldBeNil)
+
+			Convey("Objects should be read from both the quarantine directory and the repo", func() {
+				_, err := repo.CommitObject(pushed)
+				So(err, ShouldBeNil)
+				_, err = repo.TagObject(tag)
+				So(err, ShouldBeNil)
+				_, err = repo.CommitObject(base)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Only the commits reachable from the refs of the repo should already exist", func() {
+				existing, err := getExistingCommits(repo)
+				So(err, ShouldBeNil)
+				So(existing, ShouldContainKey, base)
+				So(existing, ShouldNotContainKey, pushed)
+			})
+
+			Convey("The commits of each ref should be the new ones that were not returned for another ref", func() {
+				seen, _ := getExistingCommits(repo)
+
+				commits, err := GetPushRangeCommits(repo, PushUpdate{OldRev: base.String(), NewRev: pushed.String(), RefName: "refs/heads/master"}, seen)
+				So(err, ShouldBeNil)
+				So(commits, ShouldHaveLength, 1)
+				So(commits[0].Hash, ShouldEqual, pushed)
+
+				commits, err = GetPushRangeCommits(repo, PushUpdate{OldRev: ZeroCommitID, NewRev: branched.String(), RefName: "refs/heads/feature"}, seen)
+				So(err, ShouldBeNil)
+				So(commits, ShouldHaveLength, 1)
+				So(commits[0].Hash, ShouldEqual, branched)
+
+				commits, err = GetPushRangeCommits(repo, PushUpdate{OldRev: ZeroCommitID, NewRev: tag.String(), RefName: "refs/tags/v1.0.0"}, seen)
+				So(err, ShouldBeNil)
+				So(commits, ShouldBeEmpty)
+			})
+
+			Convey("A new branch should only have the commits that are not on an existing ref", func() {
+				seen, _ := getExistingCommits(repo)
+				commits, err := GetPushRangeCommits(repo, PushUpdate{OldRev: ZeroCommitID, NewRev: branched.String(), RefName: "refs/heads/feature"}, seen)
+
+				So(err, ShouldBeNil)
+				So(commits, ShouldHaveLength, 2)
+				So(commits[0].Hash, ShouldEqual, branched)
+				So(commits[1].Hash, ShouldEqual, pushed)
+			})
+
+			Convey("An annotated tag should have the commits of the commit it tags", func() {
+				seen, _ := getExistingCommits(repo)
+				commits, err := GetPushRangeCommits(repo, PushUpdate{OldRev: ZeroCommitID, NewRev: tag.String(), RefName: "refs/tags/v1.0.0"}, seen)
+
+				So(err, ShouldBeNil)
+				So(commits, ShouldHaveLength, 2)
+				So(commits[0].Hash, ShouldEqual, branched)
+			})
+
+			Convey("A branch at an existing commit should have no commits", func() {
+				seen, _ := getExistingCommits(repo)
+				commits, err := GetPushRangeCommits(repo, PushUpdate{OldRev: ZeroCommitID, NewRev: base.String(), RefName: "refs/heads/copy"}, seen)
+
+				So(err, ShouldBeNil)
+				So(commits, ShouldBeEmpty)
+			})
+
+			Convey("A deleted ref should have no commits", func() {
+				seen, _ := getExistingCommits(repo)
+				commits, err := GetPushRangeCommits(repo, PushUpdate{OldRev: pushed.String(), NewRev: ZeroCommitID, RefName: "refs/heads/old"}, seen)
+
+				So(err, ShouldBeNil)
+				So(commits, ShouldBeEmpty)
+			})
+		})
+	})
+}
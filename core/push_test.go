@@ -0,0 +1,38 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParsePushUpdates(t *testing.T) {
+
+	Convey("Given the refs git passes to a pre-receive hook", t, func() {
+		input := core.ZeroCommitID + " 47c3e63a4f5d1dfae2abf4db992bbec46a96ac09 refs/heads/main\n\n" +
+			"47c3e63a4f5d1dfae2abf4db992bbec46a96ac09 " + core.ZeroCommitID + " refs/heads/old\n"
+
+		Convey("When they are parsed", func() {
+			updates, err := core.ParsePushUpdates(strings.NewReader(input))
+
+			Convey("There should be an update for each ref", func() {
+				So(err, ShouldBeNil)
+				So(len(updates), ShouldEqual, 2)
+				So(updates[0].OldRev, ShouldEqual, core.ZeroCommitID)
+				So(updates[0].RefName, ShouldEqual, "refs/heads/main")
+				So(updates[1].NewRev, ShouldEqual, core.ZeroCommitID)
+			})
+		})
+
+		Convey("When a line is malformed", func() {
+			_, err := core.ParsePushUpdates(strings.NewReader("refs/heads/main\n"))
+
+			Convey("An error should be returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -24,6 +24,9 @@ const (
 // Is this a github repo/org
 var isGithub bool
 
+// The gitlab instance raw files are fetched from, this is set from --gitlab-url
+var gitlabURL = GitLabBaseURL
+
 // binaryFS  holds a filesystem handle
 type binaryFS struct {
 	fs http.FileSystem
@@ -60,6 +63,10 @@ func NewRouter(s *Session) *gin.Engine {
 		isGithub = true
 	}
 
+	if s.ScanType == "gitlab" && s.GitlabURL != "" {
+		gitlabURL = s.GitlabURL
+	}
+
 	if s.Debug == true {
 		gin.SetMode(gin.DebugMode)
 	} else {
@@ -102,7 +109,7 @@ func fetchFile(c *gin.Context) {
 			return fmt.Sprintf("%s/%s/%s/%s%s", GithubBaseURI, c.Param("owner"), c.Param("repo"), c.Param("commit"), c.Param("path"))
 		}
 		results := CleanURLSpaces(c.Param("owner"), c.Param("repo"), c.Param("commit"), c.Param("path"))
-		return fmt.Sprintf("%s/%s/%s/%s/%s%s", GitLabBaseURL, results[0], results[1], "/-/raw/", results[2], results[3])
+		return fmt.Sprintf("%s/%s/%s/%s/%s%s", gitlabURL, results[0], results[1], "/-/raw/", results[2], results[3])
 
 	}()
 	resp, err := http.Head(fileURL)
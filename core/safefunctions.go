@@ -0,0 +1,89 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"path"
+	"strings"
+)
+
+// appliesTo will check if a safe function is scoped to a signature and a file. A safe function only applies to
+// signatures of the same part, and when it lists signatures or files it only applies to those.
+func (s SafeFunctionSignature) appliesTo(signatureID string, part string, filePath string) bool {
+	if s.part != part {
+		return false
+	}
+	if len(s.signatures) > 0 && !globMatchesAny(s.signatures, signatureID) {
+		return false
+	}
+	return len(s.files) == 0 || pathMatchesAny(s.files, filePath)
+}
+
+// globMatchesAny will check if a value matches any of the glob patterns
+func globMatchesAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// pathMatchesAny will check if the end of a path matches any of the glob patterns, so a pattern such as
+// config/*.yml matches the file wherever the repo was cloned to
+func pathMatchesAny(patterns []string, filePath string) bool {
+	filePath = strings.ReplaceAll(filePath, "\\", "/")
+	for {
+		if globMatchesAny(patterns, filePath) {
+			return true
+		}
+		i := strings.Index(filePath, "/")
+		if i < 0 {
+			return false
+		}
+		filePath = filePath[i+1:]
+	}
+}
+
+// safeFunctionFor will find the first safe function that applies to a signature and file and matches any of the
+// candidates
+func safeFunctionFor(signatureID string, part string, filePath string, candidates ...string) (SafeFunctionSignature, bool) {
+	for _, safeSig := range SafeFunctionSignatures {
+		if !safeSig.appliesTo(signatureID, part, filePath) {
+			continue
+		}
+		for _, candidate := range candidates {
+			if safeSig.match.MatchString(candidate) {
+				return safeSig, true
+			}
+		}
+	}
+	return SafeFunctionSignature{}, false
+}
+
+// isSafeMatch will check if a candidate match, or the text around it, is suppressed by a safe function and trace
+// which one when debugging
+func isSafeMatch(sess *Session, signatureID string, part string, filePath string, candidate string, surrounding ...string) bool {
+	safeSig, ok := safeFunctionFor(signatureID, part, filePath, append([]string{candidate}, surrounding...)...)
+	if ok && sess.Debug {
+		shown := candidate
+		if sess.HideSecrets {
+			shown = RedactMask
+		} else if sess.RedactSecrets {
+			shown = RedactSecret(candidate, sess.RedactPrefix, sess.RedactSuffix)
+		}
+		sess.Out.Debug("Safe function %s suppressed a match of %s in %s: %s\n", safeSig.signatureid, signatureID, filePath, shown)
+	}
+	return ok
+}
+
+// removeSafeMatches will drop the matches of a content signature that are suppressed by a safe function. The secret
+// may only be part of the match, so the line it is on is checked as well for safe functions written against the
+// text around it, such as the function it is passed to.
+func removeSafeMatches(s Signature, file MatchFile, sess *Session, matches map[string]MatchPosition) (bool, map[string]MatchPosition) {
+	for k, pos := range matches {
+		if isSafeMatch(sess, s.SignatureID(), s.Part(), file.Path, strings.SplitAfterN(k, "_", 2)[1], pos.Line) {
+			delete(matches, k)
+		}
+	}
+	return len(matches) > 0, matches
+}
@@ -0,0 +1,224 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+)
+
+// These are the static values used when generating a SARIF log
+const (
+	SarifVersion = "2.1.0"
+	SarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	SarifInfoURI = "https://github.com/N0MoreSecr3ts/wraith"
+	SarifLevel   = "error"
+)
+
+// SarifLog is the top level object of a SARIF 2.1.0 document
+type SarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+// SarifRun holds the tool information and results of a single scan session
+type SarifRun struct {
+	Tool                     SarifTool                 `json:"tool"`
+	Results                  []SarifResult             `json:"results"`
+	VersionControlProvenance []SarifVersionControlInfo `json:"versionControlProvenance,omitempty"`
+	ColumnKind               string                    `json:"columnKind,omitempty"`
+}
+
+// SarifTool describes the tool that produced the results
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+// SarifDriver holds the name and version of wraith along with the rules used during the scan
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+// SarifRule maps to a single signature that was loaded for the session
+type SarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SarifMessage `json:"shortDescription"`
+	FullDescription  SarifMessage `json:"fullDescription"`
+}
+
+// SarifMessage is a plain text message
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+// SarifResult maps to a single finding
+type SarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             SarifMessage      `json:"message"`
+	Locations           []SarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          map[string]string `json:"properties,omitempty"`
+}
+
+// SarifLocation holds the physical location of a finding
+type SarifLocation struct {
+	PhysicalLocation SarifPhysicalLocation `json:"physicalLocation"`
+}
+
+// SarifPhysicalLocation is the file and optionally the region within the file where a finding was made
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+	Region           *SarifRegion          `json:"region,omitempty"`
+}
+
+// SarifArtifactLocation is the uri of a file, relative to the uriBaseId if one is set
+type SarifArtifactLocation struct {
+	URI       string `json:"uri,omitempty"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// SarifRegion is the line and the matched content of a finding
+type SarifRegion struct {
+	StartLine   int           `json:"startLine"`
+	StartColumn int           `json:"startColumn,omitempty"`
+	EndLine     int           `json:"endLine,omitempty"`
+	EndColumn   int           `json:"endColumn,omitempty"`
+	Snippet     *SarifMessage `json:"snippet,omitempty"`
+}
+
+// SarifVersionControlInfo ties a repository and commit to the results that were found in it
+type SarifVersionControlInfo struct {
+	RepositoryURI string                `json:"repositoryUri"`
+	RevisionID    string                `json:"revisionId"`
+	MappedTo      SarifArtifactLocation `json:"mappedTo"`
+}
+
+// BuildSarifLog will generate a SARIF 2.1.0 log with a rule for every signature and a result for every finding
+func BuildSarifLog(findings []*Finding, signatures []Signature, wraithVersion string) *SarifLog {
+	rules := []SarifRule{}
+	ruleIndex := make(map[string]int)
+
+	for _, sig := range signatures {
+		if _, ok := ruleIndex[sig.SignatureID()]; ok {
+			continue
+		}
+		ruleIndex[sig.SignatureID()] = len(rules)
+		rules = append(rules, SarifRule{
+			ID:               sig.SignatureID(),
+			ShortDescription: SarifMessage{Text: sig.Description()},
+			FullDescription:  SarifMessage{Text: sig.Description()},
+		})
+	}
+
+	results := []SarifResult{}
+	var provenance []SarifVersionControlInfo
+	baseIDs := make(map[string]string)
+
+	for _, f := range findings {
+
+		// A finding from a signature that is no longer loaded still needs a rule to point at
+		idx, ok := ruleIndex[f.SignatureID]
+		if !ok {
+			idx = len(rules)
+			ruleIndex[f.SignatureID] = idx
+			rules = append(rules, SarifRule{
+				ID:               f.SignatureID,
+				ShortDescription: SarifMessage{Text: f.Description},
+				FullDescription:  SarifMessage{Text: f.Description},
+			})
+		}
+
+		location := SarifPhysicalLocation{
+			ArtifactLocation: SarifArtifactLocation{
+				URI: filepath.ToSlash(f.FilePath),
+			},
+		}
+
+		// Each repository and commit pair gets its own base id so the file uri can be resolved to the
+		// exact revision it was found in
+		if f.RepositoryURL != "" && f.CommitHash != "" {
+			key := f.RepositoryURL + "@" + f.CommitHash
+			baseID, ok := baseIDs[key]
+			if !ok {
+				baseID = fmt.Sprintf("REVISION%d", len(baseIDs))
+				baseIDs[key] = baseID
+				provenance = append(provenance, SarifVersionControlInfo{
+					RepositoryURI: f.RepositoryURL,
+					RevisionID:    f.CommitHash,
+					MappedTo:      SarifArtifactLocation{URIBaseID: baseID},
+				})
+			}
+			location.ArtifactLocation.URIBaseID = baseID
+		}
+
+		// SARIF lines start at 1, a line number of 0 means we did not find the line
+		if line, err := strconv.Atoi(f.LineNumber); err == nil && line > 0 {
+			location.Region = &SarifRegion{
+				StartLine:   line,
+				StartColumn: f.ColumnNumber,
+				EndLine:     f.EndLineNumber,
+				EndColumn:   f.EndColumnNumber,
+			}
+			if f.Content != "" {
+				location.Region.Snippet = &SarifMessage{Text: f.Content}
+			}
+		}
+
+		properties := map[string]string{
+			"action":         f.Action,
+			"commitAuthor":   f.CommitAuthor,
+			"commitHash":     f.CommitHash,
+			"repositoryName": f.RepositoryName,
+			"secretHash":     f.SecretHash,
+		}
+		if f.IntroducedCommit != "" {
+			properties["introducedCommit"] = f.IntroducedCommit
+			properties["lastPresentCommit"] = f.LastPresentCommit
+			properties["removedCommit"] = f.RemovedCommit
+			properties["presentAtHead"] = strconv.FormatBool(f.PresentAtHead)
+		}
+		if f.PullRequest != "" {
+			properties["pullRequest"] = f.PullRequest
+			properties["pullRequestUrl"] = f.PullRequestURL
+		}
+
+		results = append(results, SarifResult{
+			RuleID:    f.SignatureID,
+			RuleIndex: idx,
+			Level:     SarifLevel,
+			Message:   SarifMessage{Text: fmt.Sprintf("%s found in %s", f.Description, f.FilePath)},
+			Locations: []SarifLocation{{PhysicalLocation: location}},
+			PartialFingerprints: map[string]string{
+				"wraithFingerprint/v1": f.Fingerprint,
+			},
+			Properties: properties,
+		})
+	}
+
+	return &SarifLog{
+		Version: SarifVersion,
+		Schema:  SarifSchema,
+		Runs: []SarifRun{
+			{
+				Tool: SarifTool{
+					Driver: SarifDriver{
+						Name:           Name,
+						Version:        wraithVersion,
+						InformationURI: SarifInfoURI,
+						Rules:          rules,
+					},
+				},
+				Results:                  results,
+				VersionControlProvenance: provenance,
+				// Columns are counted in characters rather than the UTF-16 code units SARIF expects by default
+				ColumnKind: "unicodeCodePoints",
+			},
+		},
+	}
+}
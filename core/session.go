@@ -39,99 +39,157 @@ var defaultIgnorePaths = []string{"node_modules/", "vendor/bundle", "vendor/cach
 
 // DefaultValues is a map of all flag default values and other mutable variables
 var DefaultValues = map[string]interface{}{
+	"all-refs":                    false,
+	"baseline":                    "",
 	"bind-address":                "127.0.0.1",
 	"bind-port":                   9393,
+	"bitbucket-api-token":         "",
+	"bitbucket-server-api-token":  "",
+	"bitbucket-server-projects":   nil,
+	"bitbucket-server-url":        "",
+	"bitbucket-server-username":   "",
+	"bitbucket-username":          "",
+	"bitbucket-workspaces":        nil,
+	"branches":                    nil,
 	"commit-depth":                -1,
 	"config-file":                 "$HOME/.wraith/config.yaml",
 	"csv":                         false,
 	"debug":                       false,
+	"dedupe-across-repos":         false,
+	"exclude-signature-tags":      nil,
+	"exclude-signatures":          nil,
+	"fail-on-confidence":          0,
+	"fail-on-signature":           nil,
 	"add-org-members":             false,
 	"github-enterprise-url":       "",
 	"github-api-token":            "",
 	"github-enterprise-api-token": "",
 	"gitlab-targets":              nil,
 	"gitlab-api-token":            "",
+	"gitlab-url":                  GitLabBaseURL,
 	"ignore-extension":            nil,
 	"ignore-path":                 nil,
 	"in-mem-clone":                false,
 	"json":                        false,
+	"legacy-secret-id":            false,
 	"max-file-size":               10,
 	"num-threads":                 -1,
+	"only-signatures":             nil,
 	"local-paths":                 nil,
 	"scan-forks":                  false,
+	"scan-gists":                  false,
+	"scan-pull-requests":          false,
+	"scan-snippets":               false,
 	"scan-tests":                  false,
 	"scan-type":                   "",
+	"since-last-scan":             false,
 	"silent":                      false,
+	"tags":                        nil,
 	"confidence-level":            3,
+	"context-lines":               0,
 	"signature-file":              "$HOME/.wraith/signatures/default.yaml",
 	"signature-path":              "$HOME/.wraith/signatures/",
+	"signature-tags":              nil,
 	"scan-dir":                    nil,
 	"scan-file":                   nil,
 	"hide-secrets":                false,
 	"github-url":                  "https://api.github.com",
-	//"gitlab-url":                 "", // TODO set the default
-	"rules-url":               "",
-	"signatures-path":         "$HOME/.wraith/signatures/",
-	"signatures-url":          "https://github.com/N0MoreSecr3ts/wraith-signatures",
-	"signatures-version":      "",
-	"test-signatures":         false,
-	"github-enterprise-orgs":  nil,
-	"github-enterprise-repos": nil,
-	"github-orgs":             nil,
-	"github-repos":            nil,
-	"github-users":            nil,
-	"web-server":              false,
+	"repo-path":                   ".",
+	"redact-prefix":               4,
+	"redact-secrets":              false,
+	"redact-suffix":               4,
+	"rules-url":                   "",
+	"sarif":                       false,
+	"signatures-path":             "$HOME/.wraith/signatures/",
+	"signatures-url":              "https://github.com/N0MoreSecr3ts/wraith-signatures",
+	"signatures-version":          "",
+	"state-path":                  "$HOME/.wraith/state/",
+	"test-signatures":             false,
+	"github-enterprise-orgs":      nil,
+	"github-enterprise-repos":     nil,
+	"github-orgs":                 nil,
+	"github-repos":                nil,
+	"github-users":                nil,
+	"web-server":                  false,
 }
 
 // Session contains all the necessary values and parameters used during a scan
 type Session struct {
 	sync.Mutex
 
-	BindAddress         string
-	BindPort            int
-	Client              IClient `json:"-"`
-	CommitDepth         int
-	ConfidenceLevel     int
-	CSVOutput           bool
-	Debug               bool
-	ExpandOrgs          bool
-	Findings            []*Finding
-	GithubAccessToken   string
-	GithubClient        *github.Client `json:"-"`
-	GithubEnterpriseURL string
-	GithubURL           string
-	GitlabAccessToken   string
-	GitlabTargets       []string
-	GitlabURL           string
-	GithubUsers         []*github.User
-	HideSecrets         bool
-	InMemClone          bool
-	JSONOutput          bool
-	LocalPaths          []string
-	MaxFileSize         int64
-	Organizations       []*github.Organization
-	Out                 *Logger `json:"-"`
-	Repositories        []*Repository
-	Router              *gin.Engine `json:"-"`
-	SignatureVersion    string
-	ScanFork            bool
-	ScanTests           bool
-	ScanType            string
-	Signatures          []*Signature
-	Silent              bool
-	SkippableExt        []string
-	SkippablePath       []string
-	Stats               *Stats
-	Targets             []*Owner
-	Threads             int
-	UserDirtyNames      []string
-	UserDirtyOrgs       []string
-	UserDirtyRepos      []string
-	UserLogins          []string
-	UserOrgs            []string
-	UserRepos           []string
-	WebServer           bool
-	WraithVersion       string
+	AllRefs              bool
+	Baseline             *Baseline `json:"-"`
+	BindAddress          string
+	BindPort             int
+	BitbucketAccessToken string
+	BitbucketTargets     []string
+	BitbucketURL         string
+	BitbucketUsername    string
+	BlobCache            *BlobCache `json:"-"`
+	Branches             []string
+	Client               IClient `json:"-"`
+	CommitDepth          int
+	ConfidenceLevel      int
+	ContextLines         int
+	CSVOutput            bool
+	Debug                bool
+	ExcludeSignatureTags []string
+	ExcludeSignatures    []string
+	ExitCode             int
+	ExpandOrgs           bool
+	FailOnConfidence     int
+	FailOnSignature      []string
+	Findings             []*Finding
+	fingerprints         map[string]bool
+	GithubAccessToken    string
+	GithubClient         *github.Client `json:"-"`
+	GithubEnterpriseURL  string
+	GithubURL            string
+	GitlabAccessToken    string
+	GitlabTargets        []string
+	GitlabURL            string
+	GithubUsers          []*github.User
+	HideSecrets          bool
+	InMemClone           bool
+	JSONOutput           bool
+	LegacySecretID       bool
+	LocalPaths           []string
+	MaxFileSize          int64
+	OnlySignatures       []string
+	Organizations        []*github.Organization
+	Out                  *Logger `json:"-"`
+	RedactPrefix         int
+	RedactSecrets        bool
+	RedactSuffix         int
+	Repositories         []*Repository
+	Router               *gin.Engine `json:"-"`
+	SARIFOutput          bool
+	SignatureTags        []string
+	SignatureVersion     string
+	ScanFork             bool
+	ScanGists            bool
+	ScanPullRequests     bool
+	ScanSnippets         bool
+	ScanTests            bool
+	ScanType             string
+	Signatures           []*Signature
+	Silent               bool
+	SinceLastScan        bool
+	SkippableExt         []string
+	SkippablePath        []string
+	State                *ScanState `json:"-"`
+	Stats                *Stats
+	Tags                 []string
+	Targets              []*Owner
+	Threads              int
+	UserDirtyNames       []string
+	UserDirtyOrgs        []string
+	UserDirtyRepos       []string
+	UserLogins           []string
+	UserOrgs             []string
+	UserRepos            []string
+	WebServer            bool
+	WraithVersion        string
 }
 
 // githubRepository is the holds the necessary fields in a simpler structure
@@ -161,7 +219,7 @@ func SetConfig() {
 		home, err := homedir.Dir()
 		if err != nil {
 			fmt.Println(err)
-			os.Exit(1)
+			os.Exit(ExitCodeError)
 		}
 
 		viper.AddConfigPath(home + "/.wraith/")
@@ -180,25 +238,46 @@ func SetConfig() {
 // Initialize will set the initial values and options used during a scan session
 func (s *Session) Initialize(scanType string) {
 
+	s.AllRefs = WraithConfig.GetBool("all-refs")
 	s.BindAddress = WraithConfig.GetString("bind-address")
 	s.BindPort = WraithConfig.GetInt("bind-port")
+	s.Branches = WraithConfig.GetStringSlice("branches")
 	s.CommitDepth = setCommitDepth(WraithConfig.GetFloat64("commit-depth"))
 	s.CSVOutput = WraithConfig.GetBool("csv")
 	s.Debug = WraithConfig.GetBool("debug")
+
 	s.ExpandOrgs = WraithConfig.GetBool("expand-orgs")
+	s.ExcludeSignatureTags = WraithConfig.GetStringSlice("exclude-signature-tags")
+	s.ExcludeSignatures = WraithConfig.GetStringSlice("exclude-signatures")
+	s.FailOnConfidence = WraithConfig.GetInt("fail-on-confidence")
+	s.FailOnSignature = WraithConfig.GetStringSlice("fail-on-signature")
 	s.GithubEnterpriseURL = WraithConfig.GetString("github-enterprise-url")
 	s.GithubAccessToken = WraithConfig.GetString("github-api-token")
 	s.GitlabAccessToken = WraithConfig.GetString("gitlab-api-token")
 	s.GitlabTargets = WraithConfig.GetStringSlice("gitlab-targets")
+	s.GitlabURL = strings.TrimSuffix(WraithConfig.GetString("gitlab-url"), "/")
 	s.HideSecrets = WraithConfig.GetBool("hide-secrets")
 	s.InMemClone = WraithConfig.GetBool("in-mem-clone")
 	s.JSONOutput = WraithConfig.GetBool("json")
+	s.LegacySecretID = WraithConfig.GetBool("legacy-secret-id")
 	s.MaxFileSize = WraithConfig.GetInt64("max-file-size")
+	s.OnlySignatures = WraithConfig.GetStringSlice("only-signatures")
+	s.RedactPrefix = WraithConfig.GetInt("redact-prefix")
+	s.RedactSecrets = WraithConfig.GetBool("redact-secrets")
+	s.RedactSuffix = WraithConfig.GetInt("redact-suffix")
 	s.ConfidenceLevel = WraithConfig.GetInt("confidence-level")
+	s.ContextLines = WraithConfig.GetInt("context-lines")
+	s.SARIFOutput = WraithConfig.GetBool("sarif")
 	s.ScanFork = WraithConfig.GetBool("scan-forks")
+	s.ScanGists = WraithConfig.GetBool("scan-gists")
+	s.ScanPullRequests = WraithConfig.GetBool("scan-pull-requests")
+	s.ScanSnippets = WraithConfig.GetBool("scan-snippets")
 	s.ScanTests = WraithConfig.GetBool("scan-tests")
 	s.ScanType = scanType
 	s.Silent = WraithConfig.GetBool("silent")
+	s.SignatureTags = WraithConfig.GetStringSlice("signature-tags")
+	s.SinceLastScan = WraithConfig.GetBool("since-last-scan")
+	s.Tags = WraithConfig.GetStringSlice("tags")
 	s.Threads = WraithConfig.GetInt("num-threads")
 	s.WraithVersion = version.AppVersion()
 	s.WebServer = WraithConfig.GetBool("web-server")
@@ -207,6 +286,16 @@ func (s *Session) Initialize(scanType string) {
 		s.LocalPaths = WraithConfig.GetStringSlice("local-repos")
 	} else if s.ScanType == "localPath" {
 		s.LocalPaths = WraithConfig.GetStringSlice("local-paths")
+	} else if s.ScanType == "bitbucket" {
+		s.BitbucketAccessToken = WraithConfig.GetString("bitbucket-api-token")
+		s.BitbucketTargets = WraithConfig.GetStringSlice("bitbucket-workspaces")
+		s.BitbucketURL = BitbucketURL
+		s.BitbucketUsername = WraithConfig.GetString("bitbucket-username")
+	} else if s.ScanType == "bitbucket-server" {
+		s.BitbucketAccessToken = WraithConfig.GetString("bitbucket-server-api-token")
+		s.BitbucketTargets = WraithConfig.GetStringSlice("bitbucket-server-projects")
+		s.BitbucketURL = strings.TrimSuffix(WraithConfig.GetString("bitbucket-server-url"), "/")
+		s.BitbucketUsername = WraithConfig.GetString("bitbucket-server-username")
 	}
 
 	// Add the default directories to the sess if they don't already exist
@@ -236,6 +325,19 @@ func (s *Session) Initialize(scanType string) {
 	s.InitLogger()
 	s.InitThreads()
 
+	if s.SinceLastScan {
+		s.InitState()
+	}
+
+	// A single blob cache is shared by every repository when changes are deduplicated across repositories
+	if WraithConfig.GetBool("dedupe-across-repos") {
+		s.BlobCache = NewBlobCache()
+	}
+
+	if b := WraithConfig.GetString("baseline"); b != "" {
+		s.InitBaseline(SetHomeDir(b, s))
+	}
+
 	if !s.Silent && s.WebServer {
 		s.InitRouter()
 	}
@@ -252,7 +354,11 @@ func (s *Session) Initialize(scanType string) {
 			combinedSig = append(combinedSig, curSig...)
 		}
 	}
+	if len(combinedSig) == 0 && s.filtersSignatures() {
+		s.Out.Warn("No signatures are left to scan with after selecting them by ID, description and tag\n")
+	}
 	Signatures = combinedSig
+	SignatureKeywords = NewKeywordFilter(Signatures)
 }
 
 // setCommitDepth will set the commit depth for the current session. This is an ugly way of doing it
@@ -272,6 +378,11 @@ func (s *Session) Finish() {
 	s.Stats.Status = StatusFinished
 }
 
+// scansAllRefs will check if anything more than the default branch of a repository is being scanned
+func (s *Session) scansAllRefs() bool {
+	return s.AllRefs || len(s.Branches) > 0 || len(s.Tags) > 0
+}
+
 // AddTarget will add a new target to a session to be scanned during that session
 func (s *Session) AddTarget(target *Owner) {
 	s.Lock()
@@ -300,13 +411,28 @@ func (s *Session) AddRepository(repository *Repository) {
 }
 
 // AddFinding will add a finding that has been discovered during a session to the list of findings
-// for that session
-func (s *Session) AddFinding(finding *Finding) {
+// for that session. If a finding with the same fingerprint already exists at the same line and column, or is
+// part of the baseline, it is dropped and false is returned. A secret repeated within a file has the same
+// fingerprint at each place it is found, so each of them is kept.
+func (s *Session) AddFinding(finding *Finding) bool {
 	s.Lock()
 	defer s.Unlock()
 	const MaxStrLen = 100
+	if s.Baseline != nil && s.Baseline.Contains(finding.Fingerprint) {
+		s.Stats.IncrementFindingsSuppressed()
+		return false
+	}
+	if s.fingerprints == nil {
+		s.fingerprints = make(map[string]bool)
+	}
+	key := fmt.Sprintf("%s:%s:%d", finding.Fingerprint, finding.LineNumber, finding.ColumnNumber)
+	if s.fingerprints[key] {
+		return false
+	}
+	s.fingerprints[key] = true
 	s.Findings = append(s.Findings, finding)
 	s.Stats.IncrementFindingsTotal()
+	return true
 }
 
 // InitThreads will set the correct number of threads based on the commandline flags
@@ -318,6 +444,26 @@ func (s *Session) InitThreads() {
 	runtime.GOMAXPROCS(s.Threads + 2) // thread count + main + web server
 }
 
+// InitState will load the commit cursors from previous scans so only new commits are scanned
+func (s *Session) InitState() {
+	var err error
+	statePath := SetHomeDir(WraithConfig.GetString("state-path"), s)
+	s.State, err = LoadScanState(statePath)
+	if err != nil {
+		s.Out.Fatal("Unable to load the scan state from %s: %s\n", statePath, err)
+	}
+}
+
+// InitBaseline will load a baseline file so any finding that has already been triaged is not reported again
+func (s *Session) InitBaseline(filename string) {
+	var err error
+	s.Baseline, err = LoadBaseline(filename)
+	if err != nil {
+		s.Out.Fatal("Unable to load the baseline %s: %s\n", filename, err)
+	}
+	s.Out.Debug("Loaded %d findings from the baseline %s\n", len(s.Baseline.Findings), filename)
+}
+
 // InitRouter will configure and start the webserver for graphical output and status messages
 func (s *Session) InitRouter() {
 	bind := fmt.Sprintf("%s:%d", s.BindAddress, s.BindPort)
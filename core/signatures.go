@@ -5,9 +5,12 @@ import (
 	"io/ioutil"
 	"math"
 	"os"
+	"path"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"gopkg.in/src-d/go-git.v4/plumbing/object"
 	"gopkg.in/yaml.v2"
@@ -21,9 +24,16 @@ const (
 	PartContent   = "content"   // the content of the file
 )
 
+// SecretGroup is the name of the capture group that holds the secret within the match of a signature, such as
+// password\s*=\s*"(?P<secret>[^"]+)". The whole match is the secret when there is no such group.
+const SecretGroup = "secret"
+
 // Signatures holds a list of all signatures used during the session
 var Signatures []Signature
 
+// SignatureKeywords finds the keywords of the signatures so only the signatures that may match are run
+var SignatureKeywords *KeywordFilter
+
 // SafeFunctionSignatures is a collection of safe function sigs
 var SafeFunctionSignatures []SafeFunctionSignature
 
@@ -63,7 +73,7 @@ func getEntropyInt(s string) float64 {
 type Signature interface {
 	Description() string
 	Enable() int
-	ExtractMatch(file MatchFile, sess *Session, change *object.Change) (bool, map[string]int)
+	ExtractMatch(file MatchFile, sess *Session, change *object.Change) (bool, map[string]MatchPosition)
 	ConfidenceLevel() int
 	Part() string
 	SignatureID() string // TODO change id -> ID
@@ -86,6 +96,8 @@ type SafeFunctionSignature struct {
 	confidenceLevel int
 	part            string
 	signatureid     string
+	signatures      []string // The signatures the safe function applies to, all of them if empty
+	files           []string // The files the safe function applies to, all of them if empty
 }
 
 // SimpleSignature holds the information about a simple signature which is used to match a path or filename
@@ -110,18 +122,29 @@ type PatternSignature struct {
 	confidenceLevel int
 	part            string
 	signatureid     string
+	keywords        []string // At least one of these must be in the content for the regex to be run
+	entropyModel    string   // How the entropy of a match is calculated, see modelEntropy
 }
 
 // SignatureDef maps to a signature within the yaml file
 type SignatureDef struct {
-	Comment         string  `yaml:"comment"`
-	Description     string  `yaml:"description"`
-	Enable          int     `yaml:"enable"`
-	Entropy         float64 `yaml:"entropy"`
-	Match           string  `yaml:"match"`
-	ConfidenceLevel int     `yaml:"confidence-level"`
-	Part            string  `yaml:"part"`
-	SignatureID     string  `yaml:"signatureid"`
+	Comment         string   `yaml:"comment"`
+	Description     string   `yaml:"description"`
+	Enable          int      `yaml:"enable"`
+	Entropy         float64  `yaml:"entropy"`
+	Match           string   `yaml:"match"`
+	ConfidenceLevel int      `yaml:"confidence-level"`
+	Part            string   `yaml:"part"`
+	SignatureID     string   `yaml:"signatureid"`
+	Tags            []string `yaml:"tags"`
+	Keywords        []string `yaml:"keywords"`   // The regex of a signature is only run on content with one of these
+	Signatures      []string `yaml:"signatures"` // The signature IDs a safe function applies to, as glob patterns
+	Files           []string `yaml:"files"`      // The files a safe function applies to, as glob patterns
+	EntropyModel    string   `yaml:"entropy-model"`
+	Base64Entropy   float64  `yaml:"base64-entropy"`    // The thresholds and minimum lengths of an entropy signature
+	Base64MinLength int      `yaml:"base64-min-length"` // use the defaults of the model when they are not set
+	HexEntropy      float64  `yaml:"hex-entropy"`
+	HexMinLength    int      `yaml:"hex-min-length"`
 }
 
 // SignatureConfig holds the base file structure for the signatures file
@@ -130,15 +153,16 @@ type SignatureConfig struct {
 	PatternSignatures      []SignatureDef     `yaml:"PatternSignatures"`
 	SimpleSignatures       []SignatureDef     `yaml:"SimpleSignatures"`
 	SafeFunctionSignatures []SignatureDef     `yaml:"SafeFunctionSignatures"`
+	EntropySignatures      []SignatureDef     `yaml:"EntropySignatures"`
 }
 
 // ExtractMatch will attempt to match a path or file name of the given file
-func (s SimpleSignature) ExtractMatch(file MatchFile, sess *Session, change *object.Change) (bool, map[string]int) {
+func (s SimpleSignature) ExtractMatch(file MatchFile, sess *Session, change *object.Change) (bool, map[string]MatchPosition) {
 	var haystack *string
 	var bResult = false
 
 	// this is empty and could be removed but it here to streamline all the match functions
-	var results map[string]int
+	var results map[string]MatchPosition
 
 	switch s.part {
 	case PartPath:
@@ -154,7 +178,10 @@ func (s SimpleSignature) ExtractMatch(file MatchFile, sess *Session, change *obj
 		return bResult, results
 	}
 
-	return s.match == *haystack, results
+	if s.match != *haystack || isSafeMatch(sess, s.signatureid, s.part, file.Path, *haystack) {
+		return false, results
+	}
+	return bResult, results
 }
 
 // Enable sets whether as signature is active or not
@@ -183,148 +210,231 @@ func (s SimpleSignature) SignatureID() string {
 	return s.signatureid
 }
 
-// IsSafeText check against known "safe" (aka not a password) list
-func IsSafeText(sMatchString *string) bool {
+// confirmEntropy will determine correct entrophy of the string, using the entropy model of the signature, and
+// decide if we move forward with the match
+func confirmEntropy(thisMatch string, iSessionEntropy float64, model string) bool {
 	bResult := false
-	for _, safeSig := range SafeFunctionSignatures {
-		if safeSig.match.MatchString(*sMatchString) {
-			bResult = true
-		}
-	}
-	return bResult
-}
 
-// confirmEntropy will determine correct entrophy of the string and decide if we move forward with the match
-func confirmEntropy(thisMatch string, iSessionEntropy float64) bool {
-	bResult := false
-
-	iEntropy := getEntropyInt(thisMatch)
+	iEntropy := modelEntropy(thisMatch, model)
 
 	if (iSessionEntropy == 0) || (iEntropy >= iSessionEntropy) {
-		if !IsSafeText(&thisMatch) {
-			bResult = true
-		}
+		bResult = true
 	}
 
 	return bResult
 }
 
 // ExtractMatch will try and find a match within the content of the file.
-func (s PatternSignature) ExtractMatch(file MatchFile, sess *Session, change *object.Change) (bool, map[string]int) {
+func (s PatternSignature) ExtractMatch(file MatchFile, sess *Session, change *object.Change) (bool, map[string]MatchPosition) {
 
-	var haystack *string            // this is a pointer to the item we want to match
-	var bResult = false             // match result
-	results := make(map[string]int) // the secret and the line number in a map
+	var haystack *string                      // this is a pointer to the item we want to match
+	var bResult = false                       // match result
+	results := make(map[string]MatchPosition) // the secret and where it was found in a map
 
 	switch s.part {
 	case PartPath:
 		haystack = &file.Path
-		bResult = s.match.MatchString(*haystack)
+		bResult = s.match.MatchString(*haystack) && !isSafeMatch(sess, s.signatureid, s.part, file.Path, *haystack)
 	case PartFilename:
 		haystack = &file.Filename
-		bResult = s.match.MatchString(*haystack)
+		bResult = s.match.MatchString(*haystack) && !isSafeMatch(sess, s.signatureid, s.part, file.Path, *haystack)
 	case PartExtension:
 		haystack = &file.Extension
-		bResult = s.match.MatchString(*haystack)
+		bResult = s.match.MatchString(*haystack) && !isSafeMatch(sess, s.signatureid, s.part, file.Path, *haystack)
 	case PartContent:
-		var contextMatches []string
-
-		haystack := &file.Path
-		// The regex that we are going to try and match against
-		r := s.match
-
-		if PathExists(*haystack, sess) {
-			if _, err := os.Stat(*haystack); err == nil {
-				data, err := ioutil.ReadFile(*haystack)
-				if err != nil {
-					sErrAppend := fmt.Sprintf("ERROR --- Unable to open file for scanning: <%s> \nError Message: <%s>", *haystack, err)
-					results[sErrAppend] = 0 // set to zero due to error, we never have a line 0 so we can always ignore that or error on it
-					return false, results
-				}
+		return extractContentMatch(s, file, sess, change)
+	default: // TODO We need to do something with this
+		return bResult, results
+	}
+	return bResult, results
+
+}
+
+// matchContent will run the regex of the signature against a given piece of content, such as a file or
+// a staged blob, and return each secret found along with where it was found and the lines around it.
+// Positions are those of the secret, which is only part of the match when the signature has a secret group.
+func (s PatternSignature) matchContent(content string, contextLines int) (bool, map[string]MatchPosition) {
+	results := make(map[string]MatchPosition)
+
+	// Check to see if there is a match before doing the more expensive FindAll
+	if !s.match.MatchString(content) {
+		return false, results
+	}
 
-				// Check to see if there is a match in the data and if so switch to a Findall that
-				// will get a slice of all the individual matches. Doing this ahead of time saves us
-				// from looping through if it is not necessary.
-				if r.Match(data) {
-					for _, curRegexMatch := range r.FindAll(data, -1) {
-						contextMatches = append(contextMatches, string(curRegexMatch))
-					}
-					if len(contextMatches) > 0 {
-						bResult = true
-						for i, curMatch := range contextMatches {
-
-							thisMatch := string(curMatch[:])
-							thisMatch = strings.TrimSuffix(thisMatch, "\n")
-
-							bResult = confirmEntropy(thisMatch, s.entropy)
-
-							if bResult {
-								linesOfScannedFile := strings.Split(string(data), "\n")
-
-								num := fetchLineNumber(&linesOfScannedFile, thisMatch, 0)
-								results[strconv.Itoa(i)+"_"+thisMatch] = num
-							}
-						}
-						return bResult, results
-					}
+	linesOfScannedFile := strings.Split(content, "\n")
+	lines := newLineIndex(content)
+	var context []string
+	if contextLines > 0 {
+		context = contentLines(content)
+	}
+	secretGroup := s.match.SubexpIndex(SecretGroup)
+	for i, loc := range s.match.FindAllStringSubmatchIndex(content, -1) {
+		// Only the part of the match in the secret group is the secret, if the signature has one
+		start, end := loc[0], loc[1]
+		if secretGroup > 0 && loc[2*secretGroup] >= 0 {
+			start, end = loc[2*secretGroup], loc[2*secretGroup+1]
+		}
+		thisMatch := strings.TrimSuffix(content[start:end], "\n")
+
+		if confirmEntropy(thisMatch, s.entropy, s.entropyModel) {
+			pos := newMatchPosition(content, lines, linesOfScannedFile, start, start+len(thisMatch))
+			pos.setContext(context, contextLines)
+
+			// The line has been marked as a known false positive
+			if hasAllowMarker(linesOfScannedFile, pos.StartLine) {
+				continue
+			}
+			results[strconv.Itoa(i)+"_"+thisMatch] = pos
+		}
+	}
+	return len(results) > 0, results
+}
+
+// contentSignature is a signature that is matched against the content of a file, the content of a change
+// or a staged blob
+type contentSignature interface {
+	Signature
+	matchContent(content string, contextLines int) (bool, map[string]MatchPosition)
+}
+
+// extractContentMatch will find the matches of a content signature in a file. In the history of a repo only the
+// lines that were added by a change are matched, reading the whole file would report every secret in it again for
+// each commit that touched the file.
+func extractContentMatch(s contentSignature, file MatchFile, sess *Session, change *object.Change) (bool, map[string]MatchPosition) {
+	results := make(map[string]MatchPosition)
+
+	// A file that has already been read, such as a staged blob, is matched as a whole
+	if file.contentLoaded {
+		_, results = s.matchContent(file.content, sess.ContextLines)
+		return removeSafeMatches(s, file, sess, results)
+	}
+
+	if sess.ScanType != "localPath" {
+		if change == nil {
+			return false, results
+		}
+		additions := file.additions
+		if !file.additionsLoaded {
+			var err error
+			if additions, err = GetChangeAdditions(change); err != nil {
+				sess.Out.Error("Error retrieving content of change %s: %s\n", change.String(), err)
+			}
+		}
+		_, results = matchAdditions(s, additions)
+		bResult, results := removeSafeMatches(s, file, sess, results)
+
+		// The context can be outside of what was added so it comes from the whole of the new file
+		if bResult && sess.ContextLines > 0 {
+			if lines, err := GetChangeLines(change); err == nil {
+				for k, pos := range results {
+					pos.setContext(lines, sess.ContextLines)
+					results[k] = pos
 				}
 			}
 		}
+		return bResult, results
+	}
 
-		if sess.ScanType != "localPath" {
-			content, err := GetChangeContent(change)
+	if PathExists(file.Path, sess) {
+		if _, err := os.Stat(file.Path); err == nil {
+			data, err := ioutil.ReadFile(file.Path)
 			if err != nil {
-				sess.Out.Error("Error retrieving content in commit %s, change %s:  %s\n", "commit.String()", change.String(), err)
+				sErrAppend := fmt.Sprintf("ERROR --- Unable to open file for scanning: <%s> \nError Message: <%s>", file.Path, err)
+				results[sErrAppend] = MatchPosition{} // set to zero due to error, we never have a line 0 so we can always ignore that or error on it
+				return false, results
 			}
 
-			if r.Match([]byte(content)) {
-				for _, curRegexMatch := range r.FindAll([]byte(content), -1) {
-					contextMatches = append(contextMatches, string(curRegexMatch))
-				}
-				if len(contextMatches) > 0 {
-					bResult = true
-					for i, curMatch := range contextMatches {
-						thisMatch := string(curMatch[:])
-						thisMatch = strings.TrimSuffix(thisMatch, "\n")
-
-						bResult = confirmEntropy(thisMatch, s.entropy)
-
-						if bResult {
-							linesOfScannedFile := strings.Split(content, "\n")
-
-							num := fetchLineNumber(&linesOfScannedFile, thisMatch, i)
-							results[strconv.Itoa(i)+"_"+thisMatch] = num
-						}
-					}
-					return bResult, results
-				}
-			}
+			_, results = s.matchContent(string(data), sess.ContextLines)
+			return removeSafeMatches(s, file, sess, results)
 		}
+	}
+	return false, results
+}
 
-	default: // TODO We need to do something with this
-		return bResult, results
+// matchAdditions will run the signature against each block of lines added by a change, so a match can not span
+// lines that are not next to each other, and set the line numbers to where the matches are in the new file.
+func matchAdditions(s contentSignature, additions []ChangeAddition) (bool, map[string]MatchPosition) {
+	results := make(map[string]MatchPosition)
+	next := 0
+	for _, addition := range additions {
+		// The index of each match is kept in order by moving it past the matches in the earlier blocks
+		base := next
+		_, matches := s.matchContent(addition.Content, 0)
+		for k, pos := range matches {
+			parts := strings.SplitN(k, "_", 2)
+			i, _ := strconv.Atoi(parts[0])
+			if base+i >= next {
+				next = base + i + 1
+			}
+			pos.StartLine += addition.Line - 1
+			pos.EndLine += addition.Line - 1
+			results[strconv.Itoa(base+i)+"_"+parts[1]] = pos
+		}
 	}
-	return bResult, results
+	return len(results) > 0, results
+}
+
+// newMatchPosition will find where the bytes from start to end are in some content and the lines they are on
+func newMatchPosition(content string, lines lineIndex, contentLines []string, start, end int) MatchPosition {
+	var pos MatchPosition
+	pos.StartLine, pos.StartColumn = lines.position(content, start)
+	pos.EndLine, pos.EndColumn = lines.position(content, end)
+	pos.Line = strings.Join(contentLines[pos.StartLine-1:pos.EndLine], "\n")
+	return pos
+}
 
+// MatchPosition is where a match was found within some content. Lines and columns start at 1, columns are
+// counted in characters and the end column is the one just after the last character of the match.
+type MatchPosition struct {
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+	Line        string   // The whole of the lines the match is on
+	Before      []string // The lines of context before the match
+	After       []string // The lines of context after the match
 }
 
-// fetchLineNumber will read a file line by line and when the match is found, save the line number.
-// It manages multiple matches in a file by way of the count and an index
-func fetchLineNumber(input *[]string, thisMatch string, idx int) int {
-	linesOfScannedFile := *input
-	lineNumIndexMap := make(map[int]int)
+// setContext will set up to the given number of lines before and after the match from the lines of the content
+func (p *MatchPosition) setContext(lines []string, n int) {
+	if n <= 0 || p.StartLine < 1 || p.EndLine > len(lines) {
+		return
+	}
+	start := p.StartLine - 1 - n
+	if start < 0 {
+		start = 0
+	}
+	end := p.EndLine + n
+	if end > len(lines) {
+		end = len(lines)
+	}
+	p.Before = lines[start : p.StartLine-1]
+	p.After = lines[p.EndLine:end]
+}
 
-	count := 0
+// contentLines will split content into its lines, a newline at the end of the content does not start another line
+func contentLines(content string) []string {
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
 
-	for i, line := range linesOfScannedFile {
-		if strings.Contains(line, thisMatch) {
+// lineIndex holds the byte offset that each line of some content starts at
+type lineIndex []int
 
-			// We need to add 1 here as the index starts at zero so every line number would be line -1 normally
-			lineNumIndexMap[count] = i + 1
-			count = count + 1
+// newLineIndex will find the offset of the start of each line in the content
+func newLineIndex(content string) lineIndex {
+	starts := lineIndex{0}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			starts = append(starts, i+1)
 		}
 	}
-	return lineNumIndexMap[idx]
+	return starts
+}
+
+// position will turn a byte offset within the content into a line and a column
+func (l lineIndex) position(content string, offset int) (int, int) {
+	i := sort.Search(len(l), func(i int) bool { return l[i] > offset }) - 1
+	return i + 1, utf8.RuneCountInString(content[l[i]:offset]) + 1
 }
 
 // Enable sets whether as signature is active or not
@@ -380,8 +490,8 @@ func (s SafeFunctionSignature) SignatureID() string {
 }
 
 // ExtractMatch is a placeholder to ensure min code complexity and allow the reuse of the functions
-func (s SafeFunctionSignature) ExtractMatch(file MatchFile, sess *Session, change *object.Change) (bool, map[string]int) {
-	var results map[string]int
+func (s SafeFunctionSignature) ExtractMatch(file MatchFile, sess *Session, change *object.Change) (bool, map[string]MatchPosition) {
+	var results map[string]MatchPosition
 
 	return false, results
 }
@@ -396,7 +506,7 @@ func LoadSignatures(filePath string, mLevel int, sess *Session) []Signature { //
 
 	if err != nil {
 		sess.Out.Error("Failed to load signatures file %s: %s\n", filePath, err.Error())
-		os.Exit(2)
+		os.Exit(ExitCodeError)
 	}
 
 	signaturesMetaData := SignaturesMetaData{
@@ -411,7 +521,7 @@ func LoadSignatures(filePath string, mLevel int, sess *Session) []Signature { //
 	var PatternSignatures []PatternSignature
 	for _, curSig := range c.SimpleSignatures {
 
-		if curSig.Enable > 0 && curSig.ConfidenceLevel >= mLevel {
+		if curSig.Enable > 0 && curSig.ConfidenceLevel >= mLevel && sess.signatureSelected(curSig) {
 
 			var part string
 			switch strings.ToLower(curSig.Part) {
@@ -441,7 +551,7 @@ func LoadSignatures(filePath string, mLevel int, sess *Session) []Signature { //
 	}
 
 	for _, curSig := range c.PatternSignatures {
-		if curSig.Enable > 0 && curSig.ConfidenceLevel >= mLevel {
+		if curSig.Enable > 0 && curSig.ConfidenceLevel >= mLevel && sess.signatureSelected(curSig) {
 			var part string
 			switch strings.ToLower(curSig.Part) {
 			case "partpath":
@@ -466,6 +576,8 @@ func LoadSignatures(filePath string, mLevel int, sess *Session) []Signature { //
 				curSig.ConfidenceLevel,
 				part,
 				curSig.SignatureID,
+				normalizeKeywords(curSig.Keywords),
+				entropyModelName(curSig.EntropyModel, sess),
 			})
 		}
 	}
@@ -495,11 +607,20 @@ func LoadSignatures(filePath string, mLevel int, sess *Session) []Signature { //
 				curSig.ConfidenceLevel,
 				part,
 				curSig.SignatureID,
+				curSig.Signatures,
+				curSig.Files,
 			})
 		}
 	}
 
-	idx := len(PatternSignatures) + len(SimpleSignatures)
+	var EntropySignatures []EntropySignature
+	for _, curSig := range c.EntropySignatures {
+		if curSig.Enable > 0 && curSig.ConfidenceLevel >= mLevel && sess.signatureSelected(curSig) {
+			EntropySignatures = append(EntropySignatures, newEntropySignature(curSig, sess))
+		}
+	}
+
+	idx := len(PatternSignatures) + len(SimpleSignatures) + len(EntropySignatures)
 
 	Signatures := make([]Signature, idx)
 	jdx := 0
@@ -513,7 +634,59 @@ func LoadSignatures(filePath string, mLevel int, sess *Session) []Signature { //
 		jdx++
 	}
 
-	// TODO are we loading the safe ones somewhere
+	for _, v := range EntropySignatures {
+		Signatures[jdx] = v
+		jdx++
+	}
 
 	return Signatures
 }
+
+// filtersSignatures will check if any signatures have been selected or excluded on the command line
+func (s *Session) filtersSignatures() bool {
+	return len(s.OnlySignatures) > 0 || len(s.ExcludeSignatures) > 0 ||
+		len(s.SignatureTags) > 0 || len(s.ExcludeSignatureTags) > 0
+}
+
+// signatureSelected will check if a signature should be used in the scan. A signature can be selected or excluded
+// by its ID or a glob pattern of its ID or description, or by its tags. Exclusions win over selections.
+func (s *Session) signatureSelected(def SignatureDef) bool {
+	if len(s.OnlySignatures) > 0 && !signatureMatches(def, s.OnlySignatures) {
+		return false
+	}
+	if len(s.SignatureTags) > 0 && !hasSignatureTag(def, s.SignatureTags) {
+		return false
+	}
+	return !signatureMatches(def, s.ExcludeSignatures) && !hasSignatureTag(def, s.ExcludeSignatureTags)
+}
+
+// signatureMatches will check if the ID or description of a signature matches any of the patterns, ignoring case
+func signatureMatches(def SignatureDef, patterns []string) bool {
+	id := strings.ToLower(def.SignatureID)
+	description := strings.ToLower(def.Description)
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == id {
+			return true
+		}
+		if ok, _ := path.Match(p, id); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, description); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSignatureTag will check if a signature has any of the tags, ignoring case
+func hasSignatureTag(def SignatureDef, tags []string) bool {
+	for _, tag := range def.Tags {
+		for _, t := range tags {
+			if strings.EqualFold(strings.TrimSpace(t), tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/index"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// getStagedEntries will compare the git index to the tree of HEAD and return every entry that has been
+// added or modified. Deleted files are not in the index so there is nothing to scan for them.
+func getStagedEntries(repository *git.Repository) ([]*index.Entry, error) {
+	idx, err := repository.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	// On the initial commit there is no HEAD, so everything in the index is staged
+	var headTree *object.Tree
+	ref, err := repository.Head()
+	if err == nil {
+		commit, err := repository.CommitObject(ref.Hash())
+		if err != nil {
+			return nil, err
+		}
+		headTree, err = commit.Tree()
+		if err != nil {
+			return nil, err
+		}
+	} else if err != plumbing.ErrReferenceNotFound {
+		return nil, err
+	}
+
+	var staged []*index.Entry
+	for _, entry := range idx.Entries {
+		if headTree != nil {
+			if file, err := headTree.File(entry.Name); err == nil && file.Hash == entry.Hash {
+				continue
+			}
+		}
+		staged = append(staged, entry)
+	}
+	return staged, nil
+}
+
+// ScanStaged will scan the blobs that are staged in the index of a local repo. This is what is about to be
+// committed, which may be different from what is in the working tree, so it is read from the object store.
+func ScanStaged(repoPath string, sess *Session) {
+	sess.Stats.Status = StatusAnalyzing
+
+	repository, err := git.PlainOpenWithOptions(repoPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		sess.Out.Fatal("Unable to open the git repo at %s: %s\n", repoPath, err)
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		sess.Out.Fatal("Unable to open the working tree of %s: %s\n", repoPath, err)
+	}
+	root := worktree.Filesystem.Root()
+	repoOwner, repoName := GetRepositoryIdentity(repository, root)
+
+	entries, err := getStagedEntries(repository)
+	if err != nil {
+		sess.Out.Fatal("Unable to read the index of %s: %s\n", repoPath, err)
+	}
+
+	sess.Out.Important("Analyzing %d staged %s...\n", len(entries), Pluralize(len(entries), "file", "files"))
+
+	// Load any paths, signatures or secrets the repo has marked as known false positives
+	ignore := LoadWraithIgnore(root)
+
+	for _, entry := range entries {
+		sess.Stats.IncrementFilesTotal()
+
+		if !sess.ScanTests && isTestFileOrPath(entry.Name) {
+			sess.Stats.IncrementFilesIgnored()
+			sess.Out.Debug("%s is a test file and being ignored\n", entry.Name)
+			continue
+		}
+
+		if int64(entry.Size) > sess.MaxFileSize*1024*1024 {
+			sess.Stats.IncrementFilesIgnored()
+			sess.Out.Debug("%s is too large\n", entry.Name)
+			continue
+		}
+
+		matchFile := newMatchFile(entry.Name)
+		if matchFile.isSkippable(sess) || ignore.IsIgnoredPath(filepath.Join(root, entry.Name)) {
+			sess.Stats.IncrementFilesIgnored()
+			sess.Out.Debug("%s is skippable and being ignored\n", entry.Name)
+			continue
+		}
+
+		blob, err := repository.BlobObject(entry.Hash)
+		if err != nil {
+			sess.Out.Error("Unable to read the staged blob for %s: %s\n", entry.Name, err)
+			sess.Stats.IncrementScanFailures()
+			continue
+		}
+		reader, err := blob.Reader()
+		if err != nil {
+			sess.Out.Error("Unable to read the staged blob for %s: %s\n", entry.Name, err)
+			sess.Stats.IncrementScanFailures()
+			continue
+		}
+		data, err := ioutil.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			sess.Out.Error("Unable to read the staged blob for %s: %s\n", entry.Name, err)
+			sess.Stats.IncrementScanFailures()
+			continue
+		}
+
+		sess.Stats.IncrementFilesScanned()
+		dirtyFile := false
+
+		// Content signatures are run against the staged blob, everything else only needs the path
+		matchFile.setContent(string(data))
+
+		// Only the signatures with keywords in the staged blob may match
+		var keywords map[string]bool
+		if SignatureKeywords != nil {
+			keywords = SignatureKeywords.Find(matchFile.content)
+		}
+
+		for _, signature := range Signatures {
+			if !SignatureKeywords.MayMatch(signature, keywords) {
+				continue
+			}
+
+			bMatched, matchMap := signature.ExtractMatch(matchFile, sess, nil)
+			if !bMatched {
+				continue
+			}
+			dirtyFile = true
+
+			for k, v := range matchMap {
+				finding := newFinding(sess, signature, k, v, entry.Name, "Staged", nil)
+				finding.RepositoryName = repoName
+				finding.RepositoryOwner = repoOwner
+				finding.Initialize(sess)
+
+				if ignore.IsIgnoredFinding(finding) {
+					sess.Stats.IncrementFindingsSuppressed()
+					continue
+				}
+				if !sess.AddFinding(finding) {
+					continue
+				}
+				realTimeOutput(finding, sess)
+			}
+		}
+
+		if dirtyFile {
+			sess.Stats.IncrementFilesDirty()
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package core represents the core functionality of all commands
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StateFileName is the name of the file, within the state path, that holds the commit cursors
+const StateFileName = "cursors.json"
+
+// ScanCursor records the last commit that was successfully scanned for a given repository and branch, along with
+// the tips of every ref that was scanned such as other branches and pull requests
+type ScanCursor struct {
+	RepositoryID   int64
+	RepositoryName string
+	Branch         string
+	CommitHash     string
+	Commits        []string `json:",omitempty"`
+	ScannedAt      time.Time
+}
+
+// ScanState holds the commit cursors of all previous scans, keyed by scan type, repository and branch
+type ScanState struct {
+	sync.Mutex
+
+	path    string
+	Cursors map[string]*ScanCursor
+}
+
+// LoadScanState will read in the state file from a given directory. If the file does not exist yet
+// an empty state is returned and the file will be created on the first save.
+func LoadScanState(dir string) (*ScanState, error) {
+	state := &ScanState{
+		path:    filepath.Join(dir, StateFileName),
+		Cursors: make(map[string]*ScanCursor),
+	}
+
+	data, err := ioutil.ReadFile(state.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("unable to parse state file %s: %s", state.path, err)
+	}
+
+	if state.Cursors == nil {
+		state.Cursors = make(map[string]*ScanCursor)
+	}
+	return state, nil
+}
+
+// Cursor will return the last scanned commit hash for a key or an empty string if it has never been scanned
+func (s *ScanState) Cursor(key string) string {
+	s.Lock()
+	defer s.Unlock()
+	if c, ok := s.Cursors[key]; ok {
+		return c.CommitHash
+	}
+	return ""
+}
+
+// Commits will return the tips of the refs that were scanned for a key. Cursors saved before the tips were kept
+// only have the last scanned commit of the default branch.
+func (s *ScanState) Commits(key string) []string {
+	s.Lock()
+	defer s.Unlock()
+	c, ok := s.Cursors[key]
+	if !ok {
+		return nil
+	}
+	if len(c.Commits) > 0 {
+		return c.Commits
+	}
+	if c.CommitHash != "" {
+		return []string{c.CommitHash}
+	}
+	return nil
+}
+
+// SetCursor will set the last scanned commit for a given key
+func (s *ScanState) SetCursor(key string, cursor *ScanCursor) {
+	s.Lock()
+	defer s.Unlock()
+	s.Cursors[key] = cursor
+}
+
+// Save will write the state to disk, creating the state directory if it does not exist
+func (s *ScanState) Save() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file first so an interrupted scan can not leave a truncated state file behind
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// stateKey will generate the key used to store the cursor of a repository. Local repositories do not have
+// a stable id as it is generated from the HEAD commit, so the path to the repo is used instead.
+func stateKey(sess *Session, repo *Repository) string {
+	id := fmt.Sprintf("%d", *repo.ID)
+	if sess.ScanType == "localGit" {
+		id = *repo.CloneURL
+	}
+	return fmt.Sprintf("%s/%s/%s", sess.ScanType, id, *repo.DefaultBranch)
+}
@@ -0,0 +1,54 @@
+package core_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestScanState(t *testing.T) {
+
+	Convey("Given a state directory", t, func() {
+		dir, err := ioutil.TempDir("", "wraith")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		Convey("When no state file exists", func() {
+			state, err := core.LoadScanState(dir)
+
+			Convey("An empty state should be returned", func() {
+				So(err, ShouldBeNil)
+				So(state.Cursor("github/1/main"), ShouldEqual, "")
+			})
+		})
+
+		Convey("When a cursor is saved", func() {
+			state, _ := core.LoadScanState(dir)
+			state.SetCursor("github/1/main", &core.ScanCursor{RepositoryID: 1, Branch: "main", CommitHash: "abc123"})
+			So(state.Save(), ShouldBeNil)
+
+			Convey("It should be available when the state is loaded again", func() {
+				reloaded, err := core.LoadScanState(dir)
+				So(err, ShouldBeNil)
+				So(reloaded.Cursor("github/1/main"), ShouldEqual, "abc123")
+				So(reloaded.Cursor("github/1/develop"), ShouldEqual, "")
+			})
+		})
+
+		Convey("When a cursor has the tips of the refs that were scanned", func() {
+			state, _ := core.LoadScanState(dir)
+			state.SetCursor("github/1/main", &core.ScanCursor{CommitHash: "abc123", Commits: []string{"abc123", "def456"}})
+			state.SetCursor("github/2/main", &core.ScanCursor{CommitHash: "abc123"})
+
+			Convey("All of the tips should be used, or the last scanned commit of a cursor without them", func() {
+				So(state.Commits("github/1/main"), ShouldResemble, []string{"abc123", "def456"})
+				So(state.Commits("github/2/main"), ShouldResemble, []string{"abc123"})
+				So(state.Commits("github/3/main"), ShouldBeNil)
+			})
+		})
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -28,7 +30,10 @@ type Stats struct { // TODO alpha sort this
 	FilesIgnored        int       // The number of files ignored (tests, extensions, paths)
 	FilesTotal          int       // The total number of files that were processed
 	FilesDirty          int
+	FilesDeduplicated   int // The number of files that were not matched again as the same change was already scanned
 	FindingsTotal       int // The total number of findings. There can be more than one finding per file and more than one finding of the same type in a file
+	FindingsSuppressed  int // The number of findings that were dropped because they are known, such as being in a baseline
+	ScanFailures        int // The number of repos, commits and files that could not be scanned
 	Users               int // Github users
 	Targets             int // The number of dirs, people, orgs, etc on the command line or config file (what do you want wraith to enumerate on)
 	Repositories        int // This will point to RepositoriesScanned
@@ -62,6 +67,13 @@ func (s *Stats) IncrementFilesScanned() {
 	s.Files++
 }
 
+// IncrementFilesDeduplicated will bump the number of files that were skipped as the same change was already scanned.
+func (s *Stats) IncrementFilesDeduplicated() {
+	s.Lock()
+	defer s.Unlock()
+	s.FilesDeduplicated++
+}
+
 // IncrementFilesIgnored will bump the number of files that have been ignored for various reasons.
 func (s *Stats) IncrementFilesIgnored() {
 	s.Lock()
@@ -78,6 +90,22 @@ func (s *Stats) IncrementFindingsTotal() {
 	s.Findings++
 }
 
+// IncrementFindingsSuppressed will bump the number of findings that were matched but not reported as they
+// are already known.
+func (s *Stats) IncrementFindingsSuppressed() {
+	s.Lock()
+	defer s.Unlock()
+	s.FindingsSuppressed++
+}
+
+// IncrementScanFailures will bump the number of repositories, commits and files that could not be scanned, such as
+// a repository that could not be cloned or a commit that could not be diffed. Any of these fail the scan.
+func (s *Stats) IncrementScanFailures() {
+	s.Lock()
+	defer s.Unlock()
+	s.ScanFailures++
+}
+
 // IncrementRepositoriesTotal will bump the total number of repositories that have been discovered.
 // This will include empty ones as well as those that had errors
 func (s *Stats) IncrementRepositoriesTotal() {
@@ -168,6 +196,7 @@ func PrintDebug(sess *Session) {
 	sess.Out.Debug("\nMax file size............%d", maxFileSize)
 	sess.Out.Debug("\nJSON output..............%v", sess.JSONOutput)
 	sess.Out.Debug("\nCSV output...............%v", sess.CSVOutput)
+	sess.Out.Debug("\nSARIF output.............%v", sess.SARIFOutput)
 	sess.Out.Debug("\nSilent output............%v", sess.Silent)
 	sess.Out.Debug("\nWeb server enabled.......%v", sess.WebServer)
 	sess.Out.Debug("\n")
@@ -180,11 +209,13 @@ func PrintSessionStats(sess *Session) {
 	sess.Out.Important("\n")
 	sess.Out.Important("-------Findings------\n")
 	sess.Out.Info("Total Findings......: %d\n", sess.Stats.Findings)
+	sess.Out.Info("Findings Suppressed.: %d\n", sess.Stats.FindingsSuppressed)
 	sess.Out.Important("\n")
 	sess.Out.Important("--------Files--------\n")
 	sess.Out.Info("Total Files.........: %d\n", sess.Stats.FilesTotal)
 	sess.Out.Info("Files Scanned.......: %d\n", sess.Stats.FilesScanned)
 	sess.Out.Info("Files Ignored.......: %d\n", sess.Stats.FilesIgnored)
+	sess.Out.Info("Files Deduplicated..: %d\n", sess.Stats.FilesDeduplicated)
 	sess.Out.Info("Files Dirty.........: %d\n", sess.Stats.FilesDirty)
 	sess.Out.Important("\n")
 	sess.Out.Important("---------SCM---------\n")
@@ -201,15 +232,60 @@ func PrintSessionStats(sess *Session) {
 	sess.Out.Info("Elapsed Time........: %s\n\n", time.Since(sess.Stats.StartedAt))
 }
 
+// EvaluateExitCode will determine the exit code of a scan. If --fail-on-confidence or --fail-on-signature are
+// set then only findings from signatures meeting either of them are counted, otherwise any finding is.
+// Findings take priority over errors as they are the more actionable result.
+func (s *Session) EvaluateExitCode(signatures []Signature) int {
+	confidence := make(map[string]int)
+	for _, sig := range signatures {
+		confidence[sig.SignatureID()] = sig.ConfidenceLevel()
+	}
+
+	for _, f := range s.Findings {
+		if s.FailOnConfidence <= 0 && len(s.FailOnSignature) == 0 {
+			return ExitCodeFindings
+		}
+		if s.FailOnConfidence > 0 && confidence[f.SignatureID] >= s.FailOnConfidence {
+			return ExitCodeFindings
+		}
+		for _, id := range s.FailOnSignature {
+			if f.SignatureID == id {
+				return ExitCodeFindings
+			}
+		}
+	}
+
+	if s.Stats != nil && s.Stats.ScanFailures > 0 {
+		return ExitCodeError
+	}
+	return ExitCodeNoFindings
+}
+
+// SortFindings will sort findings by their fingerprint, and then by where they are in the file so findings with the
+// same fingerprint are always in the same order
+func SortFindings(findings []*Finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Fingerprint != b.Fingerprint {
+			return a.Fingerprint < b.Fingerprint
+		}
+		lineA, _ := strconv.Atoi(a.LineNumber)
+		lineB, _ := strconv.Atoi(b.LineNumber)
+		if lineA != lineB {
+			return lineA < lineB
+		}
+		return a.ColumnNumber < b.ColumnNumber
+	})
+}
+
 // SummaryOutput will spit out the results of the hunt along with performance data
 func SummaryOutput(sess *Session) {
 
+	// Work out the exit code up front so the commands can exit with it once any output is done
+	sess.ExitCode = sess.EvaluateExitCode(Signatures)
+
 	// alpha sort the findings to make the results idempotent
-	if len(sess.Findings) > 0 {
-		sort.Slice(sess.Findings, func(i, j int) bool {
-			return sess.Findings[i].SecretID < sess.Findings[j].SecretID
-		})
-	}
+	SortFindings(sess.Findings)
 
 	if sess.JSONOutput {
 		if len(sess.Findings) > 0 {
@@ -245,9 +321,22 @@ func SummaryOutput(sess *Session) {
 			"Commit Message",
 			"Commit Author",
 			"File URL",
+			"Fingerprint",
+			"Secret Hash",
 			"Secret ID",
 			"Wraith Version",
 			"Signatures Version",
+			"Pull Request URL",
+			"Introduced Commit",
+			"Last Present Commit",
+			"Removed Commit",
+			"Present At HEAD",
+			"Column Number",
+			"End Line Number",
+			"End Column Number",
+			"Line",
+			"Context Before",
+			"Context After",
 		}
 		err := w.Write(header)
 		if err != nil {
@@ -268,9 +357,22 @@ func SummaryOutput(sess *Session) {
 				v.CommitMessage,
 				v.CommitAuthor,
 				v.FileURL,
+				v.Fingerprint,
+				v.SecretHash,
 				v.SecretID,
 				v.WraithVersion,
 				v.signatureVersion,
+				v.PullRequestURL,
+				v.IntroducedCommit,
+				v.LastPresentCommit,
+				v.RemovedCommit,
+				strconv.FormatBool(v.PresentAtHead),
+				strconv.Itoa(v.ColumnNumber),
+				strconv.Itoa(v.EndLineNumber),
+				strconv.Itoa(v.EndColumnNumber),
+				v.Line,
+				strings.Join(v.ContextBefore, "\n"),
+				strings.Join(v.ContextAfter, "\n"),
 			}
 			err := w.Write(line)
 			if err != nil {
@@ -279,7 +381,16 @@ func SummaryOutput(sess *Session) {
 		}
 	}
 
-	if !sess.JSONOutput && !sess.CSVOutput {
+	if sess.SARIFOutput {
+		b, err := json.MarshalIndent(BuildSarifLog(sess.Findings, Signatures, sess.WraithVersion), "", "    ")
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(string(b))
+	}
+
+	if !sess.JSONOutput && !sess.CSVOutput && !sess.SARIFOutput {
 		PrintSessionStats(sess)
 	}
 }
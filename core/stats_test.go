@@ -0,0 +1,76 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/N0MoreSecr3ts/wraith/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEvaluateExitCode(t *testing.T) {
+
+	Convey("Given a scan session", t, func() {
+		sess := &core.Session{Out: &core.Logger{}}
+
+		Convey("When nothing was found", func() {
+			Convey("The exit code should be for no findings", func() {
+				So(sess.EvaluateExitCode(nil), ShouldEqual, core.ExitCodeNoFindings)
+			})
+		})
+
+		Convey("When something was found", func() {
+			sess.Findings = []*core.Finding{{SignatureID: "sig-1"}}
+
+			Convey("The exit code should be for findings", func() {
+				So(sess.EvaluateExitCode(nil), ShouldEqual, core.ExitCodeFindings)
+			})
+
+			Convey("The exit code should only be for findings if the signature is one to fail on", func() {
+				sess.FailOnSignature = []string{"sig-2"}
+				So(sess.EvaluateExitCode(nil), ShouldEqual, core.ExitCodeNoFindings)
+
+				sess.FailOnSignature = []string{"sig-2", "sig-1"}
+				So(sess.EvaluateExitCode(nil), ShouldEqual, core.ExitCodeFindings)
+			})
+		})
+
+		Convey("When something could not be scanned", func() {
+			sess.Stats = &core.Stats{}
+			sess.Stats.IncrementScanFailures()
+
+			Convey("The exit code should be for errors", func() {
+				So(sess.EvaluateExitCode(nil), ShouldEqual, core.ExitCodeError)
+			})
+
+			Convey("Findings should take priority over the errors", func() {
+				sess.Findings = []*core.Finding{{SignatureID: "sig-1"}}
+				So(sess.EvaluateExitCode(nil), ShouldEqual, core.ExitCodeFindings)
+			})
+		})
+	})
+}
+
+func TestSortFindings(t *testing.T) {
+
+	Convey("Given findings with the same fingerprint", t, func() {
+		findings := []*core.Finding{
+			{Fingerprint: "b", LineNumber: "1"},
+			{Fingerprint: "a", LineNumber: "10"},
+			{Fingerprint: "a", LineNumber: "9", ColumnNumber: 5},
+			{Fingerprint: "a", LineNumber: "9", ColumnNumber: 2},
+		}
+
+		Convey("When they are sorted", func() {
+			core.SortFindings(findings)
+
+			Convey("They should be in the order of their lines and columns as numbers", func() {
+				So(findings[0].LineNumber, ShouldEqual, "9")
+				So(findings[0].ColumnNumber, ShouldEqual, 2)
+				So(findings[1].ColumnNumber, ShouldEqual, 5)
+				So(findings[2].LineNumber, ShouldEqual, "10")
+				So(findings[3].Fingerprint, ShouldEqual, "b")
+			})
+		})
+	})
+}